@@ -0,0 +1,123 @@
+package knife
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/alias/a")
+	pkg := k.KnifePackages()[0]
+
+	b := pkg.Types()["B"]
+	alias := ToAlias(b)
+	if alias == nil {
+		t.Fatal("B must be an alias")
+	}
+
+	if got, want := alias.Rhs.String(), "github.com/gostaticanalysis/knife/testdata/alias/a.A"; got != want {
+		t.Errorf("rhs: got %q, want %q", got, want)
+	}
+
+	if got, want := alias.Actual.String(), "github.com/gostaticanalysis/knife/testdata/alias/a.Base"; got != want {
+		t.Errorf("actual: got %q, want %q", got, want)
+	}
+
+	if alias.Object != b {
+		t.Errorf("object: got %v, want %v", alias.Object, b)
+	}
+
+	if ToAlias(pkg.Types()["Base"]) != nil {
+		t.Error("Base must not be an alias")
+	}
+
+	if named := ToNamed(b); named == nil || named.Object != pkg.Types()["Base"] {
+		t.Errorf("B must be resolved to Base: %v", named)
+	}
+
+	if ToStruct(b) == nil || ToSlice(pkg.Types()["Ints"]) == nil || ToInterface(pkg.Types()["R"]) == nil {
+		t.Error("converters must resolve aliases")
+	}
+
+	if b.Type.Unalias() != pkg.Types()["Base"].Type {
+		t.Errorf("unalias: got %v", b.Type.Unalias())
+	}
+
+	set := ToAlias(pkg.Types()["Set"])
+	if set == nil || !set.Generic || len(set.TypeParams) != 1 || !pkg.Types()["Set"].Generic {
+		t.Fatalf("Set must be a generic alias: %v", set)
+	}
+
+	intSet := pkg.Vars()["IntSet"].Type.Alias()
+	if intSet == nil || intSet.Generic || intSet.Origin != set || len(intSet.TypeArgs) != 1 {
+		t.Fatalf("Set[int] must be an instance of Set: %v", intSet)
+	}
+
+	if got, want := intSet.Actual.String(), "map[int]bool"; got != want {
+		t.Errorf("actual: got %q, want %q", got, want)
+	}
+}
+
+func TestTemplate_Alias(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/alias/a")
+
+	cases := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "alias",
+			tmpl: `{{with alias .Types.B}}{{.Rhs}} {{.Actual}}{{end}}`,
+			want: "github.com/gostaticanalysis/knife/testdata/alias/a.A github.com/gostaticanalysis/knife/testdata/alias/a.Base",
+		},
+		{
+			name: "not alias",
+			tmpl: `{{if alias .Types.Base}}NG{{else}}OK{{end}}`,
+			want: "OK",
+		},
+		{
+			name: "unalias",
+			tmpl: `{{unalias .Types.B}} {{unalias .Types.Base}}`,
+			want: "github.com/gostaticanalysis/knife/testdata/alias/a.Base github.com/gostaticanalysis/knife/testdata/alias/a.Base",
+		},
+		{
+			name: "named",
+			tmpl: `{{(named .Types.B).Object.Name}}`,
+			want: "Base",
+		},
+		{
+			name: "identical",
+			tmpl: `{{identical .Types.A .Types.Base}} {{identical .Types.B .Types.Ints}}`,
+			want: "true false",
+		},
+		{
+			name: "implements",
+			tmpl: `{{implements .Types.B .Types.R}}`,
+			want: "true",
+		},
+		{
+			name: "methods",
+			tmpl: `{{range $name, $_ := methods .Types.B}}{{$name}}{{end}}`,
+			want: "Read",
+		},
+		{
+			name: "instantiate",
+			tmpl: `{{with instantiate .Types.Set (typeof "string")}}{{.Alias.Actual}}{{end}}`,
+			want: "map[string]bool",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := k.Execute(&buf, k.Packages()[0], tt.tmpl, &ExecuteOption{}); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package analyzer provides an [analysis.Analyzer] which executes a knife template.
+// Diagnostics which are reported by diag and report in the template are reported
+// as [analysis.Diagnostic], so knife templates can be run by go vet -vettool,
+// golangci-lint and other drivers of go/analysis.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+const doc = "knife executes a knife template and reports diagnostics of diag and report in the template"
+
+// Analyzer executes a template which is given by -template or -f flag.
+// Categories of the diagnostics are their severities.
+var Analyzer = newAnalyzer("knife", doc, templateFromFlags)
+
+var (
+	flagFormat   string
+	flagTemplate string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&flagFormat, "f", "", "template string")
+	Analyzer.Flags.StringVar(&flagTemplate, "template", "", "template file")
+}
+
+func templateFromFlags() (string, error) {
+	switch {
+	case flagTemplate != "":
+		tmpl, err := os.ReadFile(flagTemplate)
+		if err != nil {
+			return "", fmt.Errorf("cannot read template: %w", err)
+		}
+		return string(tmpl), nil
+	case flagFormat != "":
+		return flagFormat, nil
+	}
+	return "", errors.New("template is not specified: use -template or -f flag")
+}
+
+// New creates an analyzer which executes the template.
+// Categories of the diagnostics are their severities.
+func New(name, tmpl string) (*analysis.Analyzer, error) {
+	if _, err := parse(&knife.TempalteData{}, tmpl); err != nil {
+		return nil, err
+	}
+
+	return newAnalyzer(name, fmt.Sprintf("%s executes a knife template", name), func() (string, error) {
+		return tmpl, nil
+	}), nil
+}
+
+// NewFromFile creates an analyzer which executes the template file.
+// The analyzer is named after the file such as "nolongparams" for nolongparams.tmpl.
+func NewFromFile(path string) (*analysis.Analyzer, error) {
+	tmpl, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read template: %w", err)
+	}
+
+	base := filepath.Base(path)
+	return New(strings.TrimSuffix(base, filepath.Ext(base)), string(tmpl))
+}
+
+func newAnalyzer(name, doc string, tmpl func() (string, error)) *analysis.Analyzer {
+	r := &runner{name: name, tmpl: sync.OnceValues(tmpl)}
+	return &analysis.Analyzer{
+		Name: name,
+		Doc:  doc,
+		Run:  r.run,
+	}
+}
+
+type runner struct {
+	name string
+	tmpl func() (string, error)
+}
+
+func (r *runner) run(pass *analysis.Pass) (any, error) {
+	tmpl, err := r.tmpl()
+	if err != nil {
+		return nil, err
+	}
+
+	u := knife.NewUniverse()
+	td := &knife.TempalteData{
+		Fset:      pass.Fset,
+		Files:     pass.Files,
+		TypesInfo: pass.TypesInfo,
+		Pkg:       pass.Pkg,
+		Universe:  u,
+		Report: func(pos token.Pos, d *knife.Diagnostic) {
+			pass.Report(analysis.Diagnostic{
+				Pos:      pos,
+				Category: d.Severity.String(),
+				Message:  d.Message,
+			})
+		},
+		// edits are suggested fixes which can be applied with -fix
+		Edit: func(e *knife.Edit) {
+			pass.Report(analysis.Diagnostic{
+				Pos:     e.Pos,
+				End:     e.End,
+				Message: e.String(),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message: e.String(),
+					TextEdits: []analysis.TextEdit{{
+						Pos:     e.Pos,
+						End:     e.End,
+						NewText: []byte(e.NewText),
+					}},
+				}},
+			})
+		},
+	}
+
+	t, err := parse(td, tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	// only diagnostics are reported
+	if err := t.Execute(io.Discard, u.Package(pass.Pkg)); err != nil {
+		return nil, fmt.Errorf("template execute: %w", err)
+	}
+
+	return nil, nil
+}
+
+func parse(td *knife.TempalteData, tmpl string) (*template.Template, error) {
+	t, err := knife.NewTemplate(td).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("template parse: %w", err)
+	}
+	return t, nil
+}
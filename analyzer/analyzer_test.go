@@ -0,0 +1,29 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/gostaticanalysis/knife/analyzer"
+)
+
+func TestNew(t *testing.T) {
+	const tmpl = `{{range .Funcs}}{{if gt (len .Signature.Params) 5}}{{diag . (printf "%s has too many parameters" .Name)}}{{end}}{{end}}`
+	a, err := analyzer.New("nolongparams", tmpl)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), a, "a")
+}
+
+func TestNew_ParseError(t *testing.T) {
+	if _, err := analyzer.New("broken", "{{range .Funcs}}"); err == nil {
+		t.Error("expected error")
+	}
+
+	if _, err := analyzer.New("unknown", "{{unknownFunc .}}"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,9 @@
+package a
+
+func F(a, b, c, d, e, f int) {} // want "F has too many parameters"
+
+func G(a int) {}
+
+type T struct{}
+
+func (T) M(a, b, c, d, e, f int) {}
@@ -0,0 +1,228 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// APIFeature is an element of an exported API of a package
+// such as a function, a method, a field of a struct or a method of an interface.
+type APIFeature struct {
+	// Package is a path of the package.
+	Package string
+	// Key identifies the feature in the package such as "func Copy",
+	// "method File.Close", "field File.Name" and "type Reader interface, Read".
+	Key string
+	// Desc describes the feature such as "func Copy(Writer, Reader) (int64, error)".
+	// Names of parameters and results are omitted.
+	Desc string
+	// Interface is a name of the interface type if the feature is a method of an interface.
+	Interface string
+}
+
+var _ fmt.Stringer = (*APIFeature)(nil)
+
+func (f *APIFeature) String() string {
+	return "pkg " + f.Package + ", " + f.Desc
+}
+
+// APILines returns lines which describe exported features of the packages
+// such as "pkg io, func Copy(Writer, Reader) (int64, error)".
+// The lines are sorted and have no duplicates so that they can be committed as a file.
+// Main, test and internal packages and test variants are ignored,
+// so the packages should be loaded without tests.
+func APILines(pkgs []*Package) []string {
+	var lines []string
+	for _, pkg := range apiPackages(pkgs) {
+		for _, f := range APIFeatures(pkg) {
+			lines = append(lines, f.String())
+		}
+	}
+	slices.Sort(lines)
+	return slices.Compact(lines)
+}
+
+// DiffAPILines returns lines which are removed from old with "-" prefix
+// and lines which are added to new with "+" prefix.
+// Both old and new must be sorted.
+func DiffAPILines(old, new []string) []string {
+	var diff []string
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case j == len(new) || (i < len(old) && old[i] < new[j]):
+			diff = append(diff, "-"+old[i])
+			i++
+		case i == len(old) || new[j] < old[i]:
+			diff = append(diff, "+"+new[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return diff
+}
+
+// APIFeatures returns exported features of the package sorted by their descriptions.
+// Methods and fields are reported only for exported types.
+func APIFeatures(pkg *Package) []*APIFeature {
+	qf := types.RelativeTo(pkg.TypesPackage)
+	var features []*APIFeature
+	add := func(key, desc, iface string) {
+		features = append(features, &APIFeature{
+			Package:   pkg.Path,
+			Key:       key,
+			Desc:      desc,
+			Interface: iface,
+		})
+	}
+
+	for name, f := range pkg.Funcs() {
+		if f.Exported {
+			tparams := typeParamsString(f.Signature.TypesSignature.TypeParams(), qf)
+			add("func "+name, "func "+name+tparams+signatureString(f.Signature, qf), "")
+		}
+	}
+
+	for name, v := range pkg.Vars() {
+		if v.Exported {
+			add("var "+name, "var "+name+" "+types.TypeString(v.Type.TypesType, qf), "")
+		}
+	}
+
+	for name, c := range pkg.Consts() {
+		if c.Exported {
+			add("const "+name, fmt.Sprintf("const %s %s = %s", name, types.TypeString(c.Type.TypesType, qf), c.Value.ExactString()), "")
+		}
+	}
+
+	for _, tn := range pkg.Types() {
+		if tn.Exported {
+			typeFeatures(tn, qf, add)
+		}
+	}
+
+	slices.SortFunc(features, func(a, b *APIFeature) int {
+		return cmp.Or(cmp.Compare(a.Desc, b.Desc), cmp.Compare(a.Key, b.Key))
+	})
+
+	// an interface may have several unexported methods
+	return slices.CompactFunc(features, func(a, b *APIFeature) bool {
+		return a.Key == b.Key
+	})
+}
+
+func typeFeatures(tn *TypeName, qf types.Qualifier, add func(key, desc, iface string)) {
+	name := tn.Name
+	if tn.IsAlias {
+		add("type "+name, "type "+name+" = "+types.TypeString(tn.Type.TypesType, qf), "")
+		return
+	}
+
+	named := tn.Type.Named()
+	if named == nil {
+		return
+	}
+
+	tparams := typeParamsString(named.TypesNamed.TypeParams(), qf)
+	under := tn.Type.Underlying()
+	switch {
+	case under.Struct() != nil:
+		add("type "+name, "type "+name+tparams+" struct", "")
+		s := under.Struct()
+		for _, fname := range s.FieldNames() {
+			f := s.Fields()[fname]
+			if !f.Exported {
+				continue
+			}
+
+			desc := fmt.Sprintf("type %s struct, %s %s", name, fname, types.TypeString(f.Type.TypesType, qf))
+			if f.Anonymous {
+				desc = fmt.Sprintf("type %s struct, embedded %s", name, types.TypeString(f.Type.TypesType, qf))
+			}
+			add("field "+name+"."+fname, desc, "")
+		}
+	case under.Interface() != nil:
+		add("type "+name, "type "+name+tparams+" interface", "")
+		iface := under.Interface()
+		for _, mname := range iface.MethodNames() {
+			m := iface.Methods()[mname]
+			key := "type " + name + " interface, " + mname
+			if !m.Exported {
+				// an interface which has unexported methods cannot be implemented by other packages
+				add("type "+name+" interface, unexported methods", "type "+name+" interface, unexported methods", name)
+				continue
+			}
+			add(key, key+signatureString(m.Signature, qf), name)
+		}
+	default:
+		add("type "+name, "type "+name+tparams+" "+types.TypeString(under.TypesType, qf), "")
+	}
+
+	for _, mname := range named.MethodNames() {
+		m := named.Methods()[mname]
+		if !m.Exported {
+			continue
+		}
+
+		recv := name
+		if _, ok := m.Signature.TypesSignature.Recv().Type().(*types.Pointer); ok {
+			recv = "*" + name
+		}
+		add("method "+name+"."+mname, "method ("+recv+") "+mname+signatureString(m.Signature, qf), "")
+	}
+}
+
+// typeParamsString returns the type parameters such as "[K comparable, V any]".
+func typeParamsString(tps *types.TypeParamList, qf types.Qualifier) string {
+	if tps.Len() == 0 {
+		return ""
+	}
+
+	params := make([]string, tps.Len())
+	for i := range tps.Len() {
+		tp := tps.At(i)
+		params[i] = tp.Obj().Name() + " " + types.TypeString(tp.Constraint(), qf)
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// signatureString returns the signature without names of parameters and results
+// such as "(io.Writer, ...any) (int, error)".
+func signatureString(sig *Signature, qf types.Qualifier) string {
+	var b strings.Builder
+	b.WriteString("(")
+	for i, p := range sig.Params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		if sig.Variadic && i == len(sig.Params)-1 {
+			s, _ := p.Type.TypesType.(*types.Slice)
+			if s != nil {
+				b.WriteString("..." + types.TypeString(s.Elem(), qf))
+				continue
+			}
+		}
+		b.WriteString(types.TypeString(p.Type.TypesType, qf))
+	}
+	b.WriteString(")")
+
+	switch len(sig.Results) {
+	case 0:
+	case 1:
+		b.WriteString(" " + types.TypeString(sig.Results[0].Type.TypesType, qf))
+	default:
+		results := make([]string, len(sig.Results))
+		for i, r := range sig.Results {
+			results[i] = types.TypeString(r.Type.TypesType, qf)
+		}
+		b.WriteString(" (" + strings.Join(results, ", ") + ")")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package knife
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAPILines(t *testing.T) {
+	// exported objects in export_test.go are not APIs
+	opt := &KnifeOption{
+		Tests:        true,
+		Variants:     VariantsAll,
+		BuildContext: BuildContext{Dir: "./testdata/apidiff/v1"},
+	}
+	k := newTestKnife(t, opt, "./...")
+
+	got := APILines(k.KnifePackages())
+	want := []string{
+		"pkg example.com/apidiff/api, const Name untyped string = \"api\"",
+		"pkg example.com/apidiff/api, const Version untyped int = 1",
+		"pkg example.com/apidiff/api, func Close()",
+		"pkg example.com/apidiff/api, func Open(string) (io.Reader, error)",
+		"pkg example.com/apidiff/api, method (*Config) Validate() error",
+		"pkg example.com/apidiff/api, type Config struct",
+		"pkg example.com/apidiff/api, type Config struct, Debug bool",
+		"pkg example.com/apidiff/api, type Config struct, Timeout int",
+		"pkg example.com/apidiff/api, type Sealed interface",
+		"pkg example.com/apidiff/api, type Sealed interface, Name() string",
+		"pkg example.com/apidiff/api, type Sealed interface, unexported methods",
+		"pkg example.com/apidiff/api, type Store interface",
+		"pkg example.com/apidiff/api, type Store interface, Get(string) (string, error)",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestDiffAPILines(t *testing.T) {
+	old := []string{"a", "b", "d"}
+	new := []string{"b", "c", "d", "e"}
+
+	got := DiffAPILines(old, new)
+	want := []string{"-a", "+c", "+e"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := DiffAPILines(old, old); len(got) != 0 {
+		t.Errorf("same lines: got %q", got)
+	}
+}
@@ -0,0 +1,211 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// APIChangeKind is a kind of an [APIChange].
+type APIChangeKind string
+
+const (
+	// APIAdded is a feature which is added.
+	APIAdded APIChangeKind = "added"
+	// APIRemoved is a feature which is removed.
+	APIRemoved APIChangeKind = "removed"
+	// APIChanged is a feature whose description is changed.
+	APIChanged APIChangeKind = "changed"
+)
+
+// APIChange is a change of an exported feature between two versions of a package.
+type APIChange struct {
+	Package string        `json:"package"`
+	Kind    APIChangeKind `json:"kind"`
+	// Key identifies the feature (see [APIFeature.Key]).
+	Key string `json:"key"`
+	// Old is a description of the old feature. It is empty if the feature is added.
+	Old string `json:"old,omitempty"`
+	// New is a description of the new feature. It is empty if the feature is removed.
+	New string `json:"new,omitempty"`
+	// Compatible reports whether users of the old version still work with the new version.
+	Compatible bool `json:"compatible"`
+}
+
+var _ fmt.Stringer = (*APIChange)(nil)
+
+func (c *APIChange) String() string {
+	switch c.Kind {
+	case APIAdded:
+		return fmt.Sprintf("%s: added %s", c.Package, c.New)
+	case APIRemoved:
+		return fmt.Sprintf("%s: removed %s", c.Package, c.Old)
+	}
+	return fmt.Sprintf("%s: changed %s to %s", c.Package, c.Old, c.New)
+}
+
+// APIDiff is a set of changes of exported APIs between two versions of packages.
+type APIDiff struct {
+	Changes []*APIChange `json:"changes"`
+}
+
+// Compatible reports whether all changes are compatible.
+func (d *APIDiff) Compatible() bool {
+	return !slices.ContainsFunc(d.Changes, func(c *APIChange) bool {
+		return !c.Compatible
+	})
+}
+
+// Incompatibles returns incompatible changes.
+func (d *APIDiff) Incompatibles() []*APIChange {
+	return slices.DeleteFunc(slices.Clone(d.Changes), func(c *APIChange) bool {
+		return c.Compatible
+	})
+}
+
+// Compatibles returns compatible changes.
+func (d *APIDiff) Compatibles() []*APIChange {
+	return slices.DeleteFunc(slices.Clone(d.Changes), func(c *APIChange) bool {
+		return !c.Compatible
+	})
+}
+
+// Semver returns a suggested semantic version bump:
+// "major" for incompatible changes, "minor" for compatible changes
+// and "patch" if the APIs are not changed.
+func (d *APIDiff) Semver() string {
+	switch {
+	case len(d.Changes) == 0:
+		return "patch"
+	case d.Compatible():
+		return "minor"
+	}
+	return "major"
+}
+
+// DiffAPI compares exported APIs of the old packages and the new packages.
+// Packages are matched by their paths. Main packages, test packages, test variants and
+// internal packages are ignored because they cannot be imported by users.
+func DiffAPI(oldPkgs, newPkgs []*Package) *APIDiff {
+	olds, news := apiPackages(oldPkgs), apiPackages(newPkgs)
+	d := &APIDiff{Changes: []*APIChange{}}
+
+	for path, oldPkg := range olds {
+		if _, ok := news[path]; !ok {
+			d.Changes = append(d.Changes, &APIChange{
+				Package: path,
+				Kind:    APIRemoved,
+				Key:     "package",
+				Old:     "package " + oldPkg.Name,
+			})
+		}
+	}
+
+	for path, newPkg := range news {
+		oldPkg, ok := olds[path]
+		if !ok {
+			d.Changes = append(d.Changes, &APIChange{
+				Package:    path,
+				Kind:       APIAdded,
+				Key:        "package",
+				New:        "package " + newPkg.Name,
+				Compatible: true,
+			})
+			continue
+		}
+		d.Changes = append(d.Changes, diffFeatures(APIFeatures(oldPkg), APIFeatures(newPkg))...)
+	}
+
+	slices.SortFunc(d.Changes, func(a, b *APIChange) int {
+		return cmp.Or(
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+
+	return d
+}
+
+func diffFeatures(olds, news []*APIFeature) []*APIChange {
+	oldByKey := make(map[string]*APIFeature, len(olds))
+	for _, f := range olds {
+		oldByKey[f.Key] = f
+	}
+
+	newByKey := make(map[string]*APIFeature, len(news))
+	for _, f := range news {
+		newByKey[f.Key] = f
+	}
+
+	// interfaces which cannot be implemented by users of the old version
+	sealed := make(map[string]bool)
+	for _, f := range olds {
+		if f.Interface != "" && strings.HasSuffix(f.Key, ", unexported methods") {
+			sealed[f.Interface] = true
+		}
+	}
+
+	var changes []*APIChange
+	for _, f := range olds {
+		nf := newByKey[f.Key]
+		switch {
+		case nf == nil:
+			changes = append(changes, &APIChange{
+				Package: f.Package,
+				Kind:    APIRemoved,
+				Key:     f.Key,
+				Old:     f.Desc,
+			})
+		case nf.Desc != f.Desc:
+			// a method of *T can be moved to T because the method set of *T has methods of T
+			compatible := strings.HasPrefix(f.Desc, "method (*") &&
+				nf.Desc == "method ("+strings.TrimPrefix(f.Desc, "method (*")
+			changes = append(changes, &APIChange{
+				Package:    f.Package,
+				Kind:       APIChanged,
+				Key:        f.Key,
+				Old:        f.Desc,
+				New:        nf.Desc,
+				Compatible: compatible,
+			})
+		}
+	}
+
+	for _, f := range news {
+		if oldByKey[f.Key] != nil {
+			continue
+		}
+
+		// adding a method to an existing interface breaks its implementations
+		_, existed := oldByKey["type "+f.Interface]
+		compatible := f.Interface == "" || !existed || sealed[f.Interface]
+		changes = append(changes, &APIChange{
+			Package:    f.Package,
+			Kind:       APIAdded,
+			Key:        f.Key,
+			New:        f.Desc,
+			Compatible: compatible,
+		})
+	}
+
+	return changes
+}
+
+// apiPackages returns packages which can be imported by users by their paths.
+func apiPackages(pkgs []*Package) map[string]*Package {
+	m := make(map[string]*Package, len(pkgs))
+	for _, pkg := range pkgs {
+		// exported objects in test files are not APIs
+		if pkg.Name == "main" || strings.HasSuffix(pkg.Name, "_test") || pkg.IsTestVariant || isInternal(pkg.Path) {
+			continue
+		}
+		m[pkg.Path] = pkg
+	}
+	return m
+}
+
+func isInternal(path string) bool {
+	return path == "internal" || strings.HasPrefix(path, "internal/") ||
+		strings.HasSuffix(path, "/internal") || strings.Contains(path, "/internal/")
+}
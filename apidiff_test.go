@@ -0,0 +1,57 @@
+package knife
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffAPI(t *testing.T) {
+	load := func(dir string) []*Package {
+		t.Helper()
+		opt := &KnifeOption{BuildContext: BuildContext{Dir: dir}}
+		k := newTestKnife(t, opt, "./...")
+		return k.KnifePackages()
+	}
+
+	d := DiffAPI(load("./testdata/apidiff/v1"), load("./testdata/apidiff/v2"))
+
+	var incompatibles []string
+	for _, c := range d.Incompatibles() {
+		incompatibles = append(incompatibles, c.String())
+	}
+
+	wantIncompatibles := []string{
+		"example.com/apidiff/api: changed const Version untyped int = 1 to const Version untyped int = 2",
+		"example.com/apidiff/api: removed func Close()",
+		"example.com/apidiff/api: changed func Open(string) (io.Reader, error) to func Open(string, ...string) (io.Reader, error)",
+		"example.com/apidiff/api: added type Store interface, Put(string, string) error",
+	}
+
+	if !slices.Equal(incompatibles, wantIncompatibles) {
+		t.Errorf("incompatibles:\ngot  %q\nwant %q", incompatibles, wantIncompatibles)
+	}
+
+	var compatibles []string
+	for _, c := range d.Compatibles() {
+		compatibles = append(compatibles, c.String())
+	}
+
+	wantCompatibles := []string{
+		"example.com/apidiff/api: added type Config struct, Retry int",
+		"example.com/apidiff/api: added func Flush()",
+		"example.com/apidiff/api: changed method (*Config) Validate() error to method (Config) Validate() error",
+		"example.com/apidiff/api: added type Sealed interface, Kind() int",
+	}
+
+	if !slices.Equal(compatibles, wantCompatibles) {
+		t.Errorf("compatibles:\ngot  %q\nwant %q", compatibles, wantCompatibles)
+	}
+
+	if got := d.Semver(); got != "major" {
+		t.Errorf("Semver: got %q, want major", got)
+	}
+
+	if got := DiffAPI(load("./testdata/apidiff/v1"), load("./testdata/apidiff/v1")).Semver(); got != "patch" {
+		t.Errorf("Semver of same versions: got %q, want patch", got)
+	}
+}
@@ -73,37 +73,41 @@ func (n *ASTNode) Val() any {
 	return constant.Val(n.Value)
 }
 
+// NewASTNode creates an [ASTNode] in a new [Universe].
 func NewASTNode(typesInfo *types.Info, n ast.Node) *ASTNode {
+	return NewUniverse().ASTNode(typesInfo, n)
+}
+
+// ASTNode returns an [ASTNode] of n in the universe.
+func (u *Universe) ASTNode(typesInfo *types.Info, n ast.Node) *ASTNode {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newASTNode(typesInfo, n)
+}
+
+func (u *Universe) newASTNode(typesInfo *types.Info, n ast.Node) *ASTNode {
 	if n == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(n)
-	cached, _ := v.(*ASTNode)
-	if cached != nil {
-		return cached
-	}
-
-	var nn ASTNode
-	cache.Store(n, &nn)
-
-	nn.Node = n
-	nn.Scope = NewScope(typesInfo.Scopes[n])
-	if id, ok := n.(*ast.Ident); ok {
-		obj := typesInfo.ObjectOf(id)
-		if obj != nil {
-			nn.Object = NewObject(obj)
-			nn.Name = obj.Name()
-			if scopeHolder, ok := obj.(interface{ Scope() *types.Scope }); ok {
-				nn.Scope = NewScope(scopeHolder.Scope())
+	return load(u, n, func(nn *ASTNode) {
+		nn.Node = n
+		nn.Scope = u.newScope(typesInfo.Scopes[n])
+		if id, ok := n.(*ast.Ident); ok {
+			obj := typesInfo.ObjectOf(id)
+			if obj != nil {
+				nn.Object = u.newObject(obj)
+				nn.Name = obj.Name()
+				if scopeHolder, ok := obj.(interface{ Scope() *types.Scope }); ok {
+					nn.Scope = u.newScope(scopeHolder.Scope())
+				}
 			}
 		}
-	}
-	if expr, ok := n.(ast.Expr); ok {
-		nn.Type = NewType(typesInfo.TypeOf(expr))
-		if tv, ok := typesInfo.Types[expr]; ok {
-			nn.Value = tv.Value
+		if expr, ok := n.(ast.Expr); ok {
+			nn.Type = u.newType(typesInfo.TypeOf(expr))
+			if tv, ok := typesInfo.Types[expr]; ok {
+				nn.Value = tv.Value
+			}
 		}
-	}
-	return &nn
+	})
 }
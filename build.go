@@ -0,0 +1,133 @@
+package knife
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// BuildContext is a set of build settings which are passed through to [packages.Config].
+type BuildContext struct {
+	// Tags is a list of build tags (e.g. "integration").
+	Tags []string
+	// GOOS overrides the target operating system.
+	GOOS string
+	// GOARCH overrides the target architecture.
+	GOARCH string
+	// Env is a list of extra environment variables (e.g. "CGO_ENABLED=0").
+	Env []string
+	// Dir is a directory in which packages are loaded.
+	Dir string
+	// Overlay maps absolute file paths to their contents.
+	Overlay map[string][]byte
+}
+
+// PackagesConfig creates a [packages.Config] with the build context.
+func (bc *BuildContext) PackagesConfig(mode packages.LoadMode, tests bool) *packages.Config {
+	cfg := &packages.Config{
+		Mode:    mode,
+		Tests:   tests,
+		Dir:     bc.Dir,
+		Overlay: bc.Overlay,
+	}
+
+	if len(bc.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(bc.Tags, ",")}
+	}
+
+	if len(bc.Env) > 0 || bc.GOOS != "" || bc.GOARCH != "" {
+		env := append(os.Environ(), bc.Env...)
+		if bc.GOOS != "" {
+			env = append(env, "GOOS="+bc.GOOS)
+		}
+		if bc.GOARCH != "" {
+			env = append(env, "GOARCH="+bc.GOARCH)
+		}
+		cfg.Env = env
+	}
+
+	return cfg
+}
+
+// RegisterFlags registers -tags, -goos, -goarch, -C and -overlay flags to fs.
+func (bc *BuildContext) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var((*tagsValue)(&bc.Tags), "tags", "a comma-separated list of build tags")
+	fs.StringVar(&bc.GOOS, "goos", "", "GOOS used to load packages")
+	fs.StringVar(&bc.GOARCH, "goarch", "", "GOARCH used to load packages")
+	fs.StringVar(&bc.Dir, "C", "", "change to dir before loading packages")
+	fs.Var((*overlayValue)(&bc.Overlay), "overlay", "a JSON file which has same format as go build -overlay")
+}
+
+type tagsValue []string
+
+func (v *tagsValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return strings.Join(*v, ",")
+}
+
+func (v *tagsValue) Set(s string) error {
+	*v = strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	return nil
+}
+
+type overlayValue map[string][]byte
+
+func (v *overlayValue) String() string {
+	return ""
+}
+
+func (v *overlayValue) Set(s string) error {
+	overlay, err := ReadOverlay(s)
+	if err != nil {
+		return err
+	}
+	*v = overlay
+	return nil
+}
+
+// ReadOverlay reads an overlay file which has same format as the -overlay flag of go build.
+// Relative paths in the file are resolved from the current directory.
+func ReadOverlay(path string) (map[string][]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read overlay: %w", err)
+	}
+
+	var file struct {
+		Replace map[string]string
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse overlay: %w", err)
+	}
+
+	overlay := make(map[string][]byte, len(file.Replace))
+	for from, to := range file.Replace {
+		abs, err := filepath.Abs(from)
+		if err != nil {
+			return nil, fmt.Errorf("overlay: %w", err)
+		}
+
+		// go build treats an empty replacement as a deleted file,
+		// but packages.Config cannot express it
+		if to == "" {
+			return nil, fmt.Errorf("overlay: deleting %s is not supported", from)
+		}
+
+		src, err := os.ReadFile(to)
+		if err != nil {
+			return nil, fmt.Errorf("overlay: %w", err)
+		}
+		overlay[abs] = src
+	}
+
+	return overlay, nil
+}
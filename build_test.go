@@ -0,0 +1,89 @@
+package knife
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBuildContext(t *testing.T) {
+	cases := []struct {
+		name string
+		bc   BuildContext
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{"Common"},
+		},
+		{
+			name: "tags",
+			bc:   BuildContext{Tags: []string{"integration"}},
+			want: []string{"Common", "Integration"},
+		},
+		{
+			name: "goos",
+			bc:   BuildContext{GOOS: "windows", GOARCH: "amd64"},
+			want: []string{"Common", "Windows"},
+		},
+		{
+			name: "dir",
+			bc:   BuildContext{Dir: "testdata"},
+			want: []string{"Common"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := "./testdata/buildctx"
+			if tt.bc.Dir != "" {
+				pattern = "./buildctx"
+			}
+
+			opt := &KnifeOption{BuildContext: tt.bc}
+			k := newTestKnife(t, opt, pattern)
+
+			pkgs := k.Packages()
+			if len(pkgs) != 1 {
+				t.Fatalf("the number of packages must be 1 but %d", len(pkgs))
+			}
+
+			got := pkgs[0].Types.Scope().Names()
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOverlay(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "overlay.go")
+	if err := os.WriteFile(src, []byte("package buildctx\n\nfunc Overlay() {}\n"), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	target, err := filepath.Abs(filepath.Join("testdata", "buildctx", "overlay.go"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	overlayFile := filepath.Join(dir, "overlay.json")
+	overlayJSON := `{"Replace": {"` + filepath.ToSlash(target) + `": "` + filepath.ToSlash(src) + `"}}`
+	if err := os.WriteFile(overlayFile, []byte(overlayJSON), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	overlay, err := ReadOverlay(overlayFile)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	opt := &KnifeOption{BuildContext: BuildContext{Overlay: overlay}}
+	k := newTestKnife(t, opt, "./testdata/buildctx")
+
+	if obj := k.Packages()[0].Types.Scope().Lookup("Overlay"); obj == nil {
+		t.Error("a function in the overlay file must be loaded")
+	}
+}
@@ -0,0 +1,349 @@
+package knife
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// CallGraphAlgorithm is an algorithm to build a call graph.
+type CallGraphAlgorithm int
+
+const (
+	// CallGraphNone does not build a call graph.
+	CallGraphNone CallGraphAlgorithm = iota
+	// CallGraphCHA builds a call graph with Class Hierarchy Analysis.
+	// It is fast but a dynamic call has edges to all methods
+	// which implement the interface method.
+	CallGraphCHA
+	// CallGraphVTA builds a call graph with Variable Type Analysis.
+	// It is more precise than CHA but slower.
+	CallGraphVTA
+)
+
+var _ flag.Value = (*CallGraphAlgorithm)(nil)
+
+func (a *CallGraphAlgorithm) String() string {
+	if a == nil {
+		return ""
+	}
+
+	switch *a {
+	case CallGraphNone:
+		return "none"
+	case CallGraphCHA:
+		return "cha"
+	case CallGraphVTA:
+		return "vta"
+	}
+	return fmt.Sprintf("CallGraphAlgorithm(%d)", int(*a))
+}
+
+// Set implements [flag.Value].
+func (a *CallGraphAlgorithm) Set(v string) error {
+	switch strings.ToLower(v) {
+	case "none":
+		*a = CallGraphNone
+	case "cha":
+		*a = CallGraphCHA
+	case "vta":
+		*a = CallGraphVTA
+	default:
+		return fmt.Errorf("unknown call graph algorithm %q: expected none, cha or vta", v)
+	}
+	return nil
+}
+
+// CallGraph is a call graph of the loaded packages.
+// Calls in their dependencies are analyzed if the dependencies have syntax
+// such as [LoadAllSyntax]. With [LoadRootSyntax], the dependencies are loaded
+// from export data, so functions of them appear in the graph as callees
+// but calls from them are not analyzed. For example, a path to os.Exit
+// through log.Fatal is not found.
+// Functions in the graph are identified by their declarations,
+// so calls in function literals are treated as calls from the enclosing function
+// and instantiations of a generic function are treated as the generic function.
+type CallGraph struct {
+	u     *Universe
+	graph *callgraph.Graph
+	nodes map[*types.Func][]*callgraph.Node
+}
+
+// NewCallGraph builds SSA of the packages and creates a call graph with the algorithm.
+// The packages must have syntax and type information.
+// Dependencies of the packages are also built if they have syntax.
+func NewCallGraph(u *Universe, pkgs []*packages.Package, algo CallGraphAlgorithm) (*CallGraph, error) {
+	prog, err := buildSSA(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var graph *callgraph.Graph
+	switch algo {
+	case CallGraphCHA:
+		graph = cha.CallGraph(prog)
+	case CallGraphVTA:
+		graph = vta.CallGraph(ssautil.AllFunctions(prog), cha.CallGraph(prog))
+	default:
+		return nil, fmt.Errorf("unsupported call graph algorithm: %s", &algo)
+	}
+	deleteWrappers(graph)
+
+	cg := &CallGraph{
+		u:     u,
+		graph: graph,
+		nodes: make(map[*types.Func][]*callgraph.Node),
+	}
+
+	for fn, n := range graph.Nodes {
+		if f := declaredFunc(fn); f != nil {
+			cg.nodes[f] = append(cg.nodes[f], n)
+		}
+	}
+
+	return cg, nil
+}
+
+// buildSSA builds SSA of the packages and their dependencies.
+// A dependency which is loaded from export data has only its members.
+// The SSA builder may not support syntax of a newer Go than golang.org/x/tools,
+// so a dependency which fails to be built is treated as if it is loaded from export data.
+// An ill-typed dependency and a package which has its own errors are also treated so,
+// because their syntax cannot be built.
+func buildSSA(pkgs []*packages.Package) (*ssa.Program, error) {
+	if len(pkgs) == 0 {
+		return nil, errors.New("no packages to build SSA")
+	}
+
+	roots := make(map[*packages.Package]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		roots[pkg] = true
+	}
+
+	exportOnly := make(map[*packages.Package]bool)
+	for {
+		prog := ssa.NewProgram(pkgs[0].Fset, ssa.InstantiateGenerics|ssa.BuildSerially)
+		created := make(map[*ssa.Package]*packages.Package)
+		packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+			if pkg.Types == nil {
+				return
+			}
+
+			// a root is also ill-typed if its dependencies have errors
+			illTyped := pkg.IllTyped && (!roots[pkg] || len(pkg.Errors) > 0)
+			files, info := pkg.Syntax, pkg.TypesInfo
+			if exportOnly[pkg] || illTyped {
+				files, info = nil, nil
+			}
+			created[prog.CreatePackage(pkg.Types, files, info, true)] = pkg
+		})
+
+		failed, err := buildPackages(prog)
+		if failed == nil {
+			return prog, nil
+		}
+
+		pkg := created[failed]
+		if roots[pkg] || exportOnly[pkg] {
+			return nil, fmt.Errorf("cannot build SSA of %s: %w", failed.Pkg.Path(), err)
+		}
+		exportOnly[pkg] = true
+	}
+}
+
+// buildPackages builds the packages one by one and returns a package whose build panics.
+func buildPackages(prog *ssa.Program) (failed *ssa.Package, err error) {
+	for _, pkg := range prog.AllPackages() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					failed, err = pkg, fmt.Errorf("%v", r)
+				}
+			}()
+			pkg.Build()
+		}()
+
+		if failed != nil {
+			return failed, err
+		}
+	}
+	return nil, nil
+}
+
+// deleteWrappers deletes nodes of synthetic functions such as wrappers
+// and connects their callers to their callees.
+// Unlike [callgraph.Graph.DeleteSyntheticNodes], it keeps functions of dependencies
+// which are created from type information without bodies.
+func deleteWrappers(g *callgraph.Graph) {
+	edges := make(map[callgraph.Edge]bool)
+	for _, n := range g.Nodes {
+		for _, e := range n.Out {
+			edges[*e] = true
+		}
+	}
+
+	for fn, n := range g.Nodes {
+		if n == g.Root || fn.Syntax() != nil || len(fn.Blocks) == 0 {
+			continue
+		}
+
+		for _, in := range n.In {
+			for _, out := range n.Out {
+				e := callgraph.Edge{Caller: in.Caller, Site: in.Site, Callee: out.Callee}
+				if !edges[e] {
+					callgraph.AddEdge(in.Caller, in.Site, out.Callee)
+					edges[e] = true
+				}
+			}
+		}
+		g.DeleteNode(n)
+	}
+}
+
+// declaredFunc returns a declared function which has the SSA function.
+// It returns nil for a synthetic function such as a package initializer.
+func declaredFunc(fn *ssa.Function) *types.Func {
+	for ; fn != nil; fn = fn.Parent() {
+		if fn.Origin() != nil {
+			fn = fn.Origin()
+		}
+
+		if f, ok := fn.Object().(*types.Func); ok {
+			return f.Origin()
+		}
+	}
+	return nil
+}
+
+func (cg *CallGraph) funcNodes(f any) []*callgraph.Node {
+	var obj types.Object
+	switch f := f.(type) {
+	case Object:
+		if f != nil {
+			obj = f.TypesObject()
+		}
+	case types.Object:
+		obj = f
+	}
+
+	tf, _ := obj.(*types.Func)
+	if tf == nil {
+		return nil
+	}
+	return cg.nodes[tf.Origin()]
+}
+
+// Callers returns functions which call f directly.
+// f is a [*Func] or a [*types.Func].
+func (cg *CallGraph) Callers(f any) []*Func {
+	return cg.neighbors(cg.funcNodes(f), func(n *callgraph.Node) []*callgraph.Node {
+		callers := make([]*callgraph.Node, len(n.In))
+		for i, e := range n.In {
+			callers[i] = e.Caller
+		}
+		return callers
+	})
+}
+
+// Callees returns functions which are called by f directly.
+// f is a [*Func] or a [*types.Func].
+func (cg *CallGraph) Callees(f any) []*Func {
+	return cg.neighbors(cg.funcNodes(f), func(n *callgraph.Node) []*callgraph.Node {
+		callees := make([]*callgraph.Node, len(n.Out))
+		for i, e := range n.Out {
+			callees[i] = e.Callee
+		}
+		return callees
+	})
+}
+
+func (cg *CallGraph) neighbors(nodes []*callgraph.Node, next func(*callgraph.Node) []*callgraph.Node) []*Func {
+	self := make(map[*callgraph.Node]bool, len(nodes))
+	for _, n := range nodes {
+		self[n] = true
+	}
+
+	seen := make(map[*types.Func]bool)
+	var funcs []*types.Func
+	for _, n := range nodes {
+		for _, m := range next(n) {
+			f := declaredFunc(m.Func)
+			// calls in function literals are calls from the enclosing function
+			if f == nil || seen[f] || (self[m] && (n.Func.Parent() != nil || m.Func.Parent() != nil)) {
+				continue
+			}
+			seen[f] = true
+			funcs = append(funcs, f)
+		}
+	}
+
+	return cg.sortedFuncs(funcs)
+}
+
+// Reaches reports whether from can reach to via calls.
+// from and to are [*Func] or [*types.Func].
+func (cg *CallGraph) Reaches(from, to any) bool {
+	targets := cg.funcNodes(to)
+	if len(targets) == 0 {
+		return false
+	}
+
+	isTarget := make(map[*callgraph.Node]bool, len(targets))
+	for _, n := range targets {
+		isTarget[n] = true
+	}
+
+	seen := make(map[*callgraph.Node]bool)
+	queue := slices.Clone(cg.funcNodes(from))
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+
+		for _, e := range n.Out {
+			if isTarget[e.Callee] {
+				return true
+			}
+			queue = append(queue, e.Callee)
+		}
+	}
+
+	return false
+}
+
+// Funcs returns all declared functions in the call graph.
+func (cg *CallGraph) Funcs() []*Func {
+	funcs := make([]*types.Func, 0, len(cg.nodes))
+	for f := range cg.nodes {
+		funcs = append(funcs, f)
+	}
+	return cg.sortedFuncs(funcs)
+}
+
+// sortedFuncs converts the functions to knife functions
+// and sorts them by their full names.
+func (cg *CallGraph) sortedFuncs(funcs []*types.Func) []*Func {
+	slices.SortFunc(funcs, func(a, b *types.Func) int {
+		return cmp.Compare(a.FullName(), b.FullName())
+	})
+
+	kfuncs := make([]*Func, len(funcs))
+	for i, f := range funcs {
+		kfuncs[i] = cg.u.Func(f)
+	}
+	return kfuncs
+}
@@ -0,0 +1,162 @@
+package knife
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCallGraph(t *testing.T) {
+	const pkg = "github.com/gostaticanalysis/knife/testdata/callgraph"
+
+	cases := []struct {
+		algo        CallGraphAlgorithm
+		callers     []string
+		callees     []string
+		runReaches  bool
+		safeReaches bool
+	}{
+		{
+			algo:        CallGraphCHA,
+			callers:     []string{pkg + ".Run", pkg + ".Safe"},
+			callees:     []string{"(" + pkg + ".exitLogger).Log", pkg + ".helper"},
+			runReaches:  true,
+			safeReaches: false,
+		},
+		{
+			// VTA knows that Run is not called with exitLogger
+			algo:        CallGraphVTA,
+			callers:     []string{pkg + ".Run", pkg + ".Safe"},
+			callees:     []string{pkg + ".helper"},
+			runReaches:  false,
+			safeReaches: false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.algo.String(), func(t *testing.T) {
+			k := newTestKnife(t, &KnifeOption{CallGraph: tt.algo}, "./testdata/callgraph")
+
+			cg := k.CallGraph()
+			prog := k.Program()
+			helper := prog.Funcs()[pkg+".helper"]
+			run := prog.Funcs()[pkg+".Run"]
+			safe := prog.Funcs()[pkg+".Safe"]
+			exit := prog.Lookup("os.Exit")
+
+			// CHA resolves dynamic calls in dependencies to all functions with the same signature
+			if got := inPackage(fullNames(cg.Callers(helper)), pkg); !slices.Equal(got, tt.callers) {
+				t.Errorf("Callers: got %q, want %q", got, tt.callers)
+			}
+
+			if got := fullNames(cg.Callees(run)); !slices.Equal(got, tt.callees) {
+				t.Errorf("Callees: got %q, want %q", got, tt.callees)
+			}
+
+			if got := cg.Reaches(run, exit); got != tt.runReaches {
+				t.Errorf("Reaches(Run, os.Exit): got %v, want %v", got, tt.runReaches)
+			}
+
+			if got := cg.Reaches(safe, exit); got != tt.safeReaches {
+				t.Errorf("Reaches(Safe, os.Exit): got %v, want %v", got, tt.safeReaches)
+			}
+
+			// calls in dependencies are analyzed
+			die := prog.Funcs()[pkg+".Die"]
+			if !cg.Reaches(die, exit) {
+				t.Error("Reaches(Die, os.Exit): got false, want true")
+			}
+
+			if got := fullNames(cg.Callees(prog.Lookup("log.Fatal"))); !slices.Contains(got, "os.Exit") {
+				t.Errorf("Callees(log.Fatal) must contain os.Exit: %q", got)
+			}
+
+			var buf strings.Builder
+			tmpl := `{{range callers (index .Funcs "helper")}}{{if eq .Package.Path "` + pkg + `"}}{{.Name}} {{end}}{{end}}`
+			if err := k.Execute(&buf, k.Packages()[0], tmpl, &ExecuteOption{}); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got, want := buf.String(), "Run Safe "; got != want {
+				t.Errorf("template: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCallGraph_LoadRoot(t *testing.T) {
+	skipIfExportDataUnsupported(t)
+
+	const pkg = "github.com/gostaticanalysis/knife/testdata/callgraph"
+
+	opt := &KnifeOption{CallGraph: CallGraphCHA, Load: LoadRootSyntax}
+	k := newTestKnife(t, opt, "./testdata/callgraph")
+
+	cg := k.CallGraph()
+	prog := k.Program()
+	die := prog.Funcs()[pkg+".Die"]
+	fatal := prog.Lookup("log.Fatal")
+
+	if !cg.Reaches(die, fatal) {
+		t.Error("Reaches(Die, log.Fatal): got false, want true")
+	}
+
+	// dependencies which are loaded from export data do not have calls
+	if cg.Reaches(die, prog.Lookup("os.Exit")) {
+		t.Error("Reaches(Die, os.Exit): got true, want false")
+	}
+}
+
+func TestCallGraph_IllTyped(t *testing.T) {
+	const broken = "github.com/gostaticanalysis/knife/testdata/broken"
+
+	cases := []struct {
+		name    string
+		pattern string
+		caller  string
+		want    []string
+	}{
+		// only the dependency has errors
+		{name: "dependency", pattern: "./testdata/brokendep", caller: "github.com/gostaticanalysis/knife/testdata/brokendep.G", want: []string{broken + ".F"}},
+		{name: "root", pattern: "./testdata/broken", caller: broken + ".F", want: nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newTestKnife(t, &KnifeOption{CallGraph: CallGraphCHA}, tt.pattern)
+
+			caller, ok := k.Program().Lookup(tt.caller).(*Func)
+			if !ok {
+				t.Fatalf("%s is not found", tt.caller)
+			}
+
+			if got := fullNames(k.CallGraph().Callees(caller)); !slices.Equal(got, tt.want) {
+				t.Errorf("callees of %s: got %q, want %q", tt.caller, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallGraph_NotBuilt(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/callgraph")
+
+	var buf strings.Builder
+	err := k.Execute(&buf, k.Packages()[0], `{{callers (index .Funcs "helper")}}`, &ExecuteOption{})
+	if err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func fullNames(funcs []*Func) []string {
+	names := make([]string, len(funcs))
+	for i, f := range funcs {
+		names[i] = f.TypesFunc.FullName()
+	}
+	return names
+}
+
+func inPackage(names []string, pkg string) []string {
+	return slices.DeleteFunc(names, func(name string) bool {
+		return !strings.HasPrefix(strings.TrimLeft(name, "(*"), pkg+".")
+	})
+}
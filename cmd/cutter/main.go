@@ -24,6 +24,12 @@ var (
 	flagTemplate  string
 	flagExtraData string
 	flagTests     bool
+	flagStrict    bool
+	flagVariants  knife.VariantPolicy
+	flagBuild     knife.BuildContext
+	flagPlatforms string
+	flagReport    bool
+	flagAll       bool
 )
 
 func init() {
@@ -32,6 +38,12 @@ func init() {
 	flag.StringVar(&flagTemplate, "template", "", "template file")
 	flag.StringVar(&flagExtraData, "data", "", "extra data (key:value,key:value)")
 	flag.BoolVar(&flagTests, "tests", true, "include test files")
+	flag.BoolVar(&flagStrict, "strict", os.Getenv("CI") != "", "fail on load, parse and type errors (enabled by default if $CI is set)")
+	flag.Var(&flagVariants, "variants", "a policy for test variants of packages (collapse|all)")
+	flagBuild.RegisterFlags(flag.CommandLine)
+	flag.StringVar(&flagPlatforms, "platforms", "", "a comma-separated list of GOOS/GOARCH pairs to load (e.g. linux/amd64,windows/amd64)")
+	flag.BoolVar(&flagReport, "platform-report", false, "print objects which exist on only some of the platforms")
+	flag.BoolVar(&flagAll, "all", false, "execute the template once with all packages")
 	flag.Parse()
 }
 
@@ -54,13 +66,20 @@ func run(ctx context.Context, args []string) error {
 	}
 
 	cutterOpt := &cutter.CutterOption{
-		Tests: flagTests,
+		Tests:        flagTests,
+		Strict:       flagStrict,
+		Variants:     flagVariants,
+		BuildContext: flagBuild,
 	}
-	c, err := cutter.New(cutterOpt, args...)
+	c, err := newCutter(cutterOpt, args)
 	if err != nil {
 		return err
 	}
 
+	if flagReport {
+		return knife.PlatformReport(os.Stdout, c.Platforms(), c.KnifePackages())
+	}
+
 	var opt cutter.Option
 	if flagExtraData != "" {
 		extraData, err := parseExtraData(flagExtraData)
@@ -78,6 +97,10 @@ func run(ctx context.Context, args []string) error {
 		}
 	}
 
+	if flagAll {
+		return c.ExecuteAll(os.Stdout, tmpl, &opt)
+	}
+
 	pkgs := c.KnifePackages()
 	readers := make([]io.Reader, len(pkgs))
 	var g gogroup.Group
@@ -112,6 +135,22 @@ func run(ctx context.Context, args []string) error {
 	return nil
 }
 
+func newCutter(opt *cutter.CutterOption, patterns []string) (*cutter.Cutter, error) {
+	if flagPlatforms == "" {
+		if flagReport {
+			return nil, fmt.Errorf("-platform-report requires -platforms")
+		}
+		return cutter.New(opt, patterns...)
+	}
+
+	platforms, err := knife.ParsePlatforms(flagPlatforms)
+	if err != nil {
+		return nil, err
+	}
+
+	return cutter.NewMatrix(opt, platforms, patterns...)
+}
+
 func parseExtraData(extraData string) (map[string]any, error) {
 	m := make(map[string]any)
 	kvs := strings.Split(extraData, ",")
@@ -18,6 +18,7 @@ var (
 	flagFormat    string
 	flagTemplate  string
 	flagExtraData string
+	flagBuild     knife.BuildContext
 )
 
 func init() {
@@ -25,6 +26,7 @@ func init() {
 	flag.StringVar(&flagFormat, "f", "{{.}}", "output format")
 	flag.StringVar(&flagTemplate, "template", "", "template file")
 	flag.StringVar(&flagExtraData, "data", "", "extra data as JSON format")
+	flagBuild.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 }
 
@@ -36,7 +38,10 @@ func main() {
 }
 
 func run() (rerr error) {
-	knifeOpt := &knife.KnifeOption{Tests: true}
+	knifeOpt := &knife.KnifeOption{
+		Tests:        true,
+		BuildContext: flagBuild,
+	}
 	k, err := knife.New(knifeOpt, flag.Args()[1:]...)
 	if err != nil {
 		return fmt.Errorf("cannot create knife: %w", err)
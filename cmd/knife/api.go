@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runAPI prints the exported API of the packages in a line per feature.
+// With -check, it compares the API with the file and fails if they differ.
+//
+//	knife [flags] api [-check api.txt] [patterns]
+func runAPI(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("api", flag.ExitOnError)
+	check := fs.String("check", "", "a file which has the expected API")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] api [-check api.txt] [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// exported objects in test files are not APIs
+	apiOpt := *opt
+	apiOpt.Tests = false
+	k, err := newKnife(&apiOpt, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	lines := knife.APILines(k.KnifePackages())
+	if *check == "" {
+		return printLines(os.Stdout, lines)
+	}
+
+	expected, err := readAPIFile(*check)
+	if err != nil {
+		return err
+	}
+
+	diff := knife.DiffAPILines(expected, lines)
+	if len(diff) == 0 {
+		return nil
+	}
+
+	fmt.Printf("--- %s\n+++ packages\n", *check)
+	if err := printLines(os.Stdout, diff); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("api: the exported API differs from %s: update it with knife api", *check)
+}
+
+// readAPIFile reads sorted lines of the file except empty lines and comments which start with "#".
+func readAPIFile(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("api: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("api: %s: %w", name, err)
+	}
+
+	slices.Sort(lines)
+	return slices.Compact(lines), nil
+}
+
+func printLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runAPIDiff compares exported APIs of the packages at two git revisions
+// and suggests a semantic version bump.
+// Each revision is checked out into a temporary worktree.
+//
+//	knife [flags] apidiff [-format text|json] <rev1> <rev2> [patterns]
+func runAPIDiff(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("apidiff", flag.ExitOnError)
+	format := fs.String("format", "text", "an output format (text|json)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] apidiff [-format text|json] <rev1> <rev2> [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("apidiff: unknown format %q: expected text or json", *format)
+	}
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return fmt.Errorf("apidiff: two revisions must be specified (e.g. v1.0.0 HEAD)")
+	}
+
+	patterns := fs.Args()[2:]
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	olds, err := loadAtRevision(opt, fs.Arg(0), patterns)
+	if err != nil {
+		return err
+	}
+
+	news, err := loadAtRevision(opt, fs.Arg(1), patterns)
+	if err != nil {
+		return err
+	}
+
+	d := knife.DiffAPI(olds, news)
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*knife.APIDiff
+			Compatible bool   `json:"compatible"`
+			Semver     string `json:"semver"`
+		}{d, d.Compatible(), d.Semver()})
+	}
+
+	return printAPIDiff(os.Stdout, d)
+}
+
+// loadAtRevision loads the packages in a temporary git worktree of the revision.
+func loadAtRevision(opt *knife.KnifeOption, rev string, patterns []string) (_ []*knife.Package, rerr error) {
+	prefix, err := git(opt.Dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "knife-apidiff-")
+	if err != nil {
+		return nil, fmt.Errorf("apidiff: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	worktree := filepath.Join(tmp, "worktree")
+	if _, err := git(opt.Dir, "worktree", "add", "--detach", worktree, rev); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if _, err := git(opt.Dir, "worktree", "remove", "--force", worktree); err != nil && rerr == nil {
+			rerr = err
+		}
+	}()
+
+	revOpt := *opt
+	revOpt.Tests = false
+	revOpt.Overlay = nil
+	revOpt.Dir = filepath.Join(worktree, filepath.FromSlash(prefix))
+	k, err := knife.New(&revOpt, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("apidiff: %s: %w", rev, err)
+	}
+
+	return k.KnifePackages(), nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func printAPIDiff(w io.Writer, d *knife.APIDiff) error {
+	var buf bytes.Buffer
+	for _, section := range []struct {
+		title   string
+		changes []*knife.APIChange
+	}{
+		{"Incompatible changes:", d.Incompatibles()},
+		{"Compatible changes:", d.Compatibles()},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(&buf, section.title)
+		for _, c := range section.changes {
+			fmt.Fprintf(&buf, "- %s\n", c)
+		}
+		fmt.Fprintln(&buf)
+	}
+
+	fmt.Fprintf(&buf, "Suggested version bump: %s\n", d.Semver())
+
+	_, err := buf.WriteTo(w)
+	return err
+}
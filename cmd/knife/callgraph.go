@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runCallGraph prints the call graph of the packages.
+// By default, it prints edges from functions of the packages.
+// With -callers, -callees or -reaches, it prints the functions.
+// If -f or -template is given, the template is executed with the functions
+// or the call graph.
+//
+//	knife [flags] callgraph [-algo cha|vta] [-callers|-callees|-reaches <pkg.Func>] [patterns]
+func runCallGraph(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("callgraph", flag.ExitOnError)
+	algo := opt.CallGraph
+	if algo == knife.CallGraphNone {
+		algo = knife.CallGraphCHA
+	}
+	fs.Var(&algo, "algo", "an algorithm to build a call graph (cha|vta)")
+	callers := fs.String("callers", "", "print functions which call the function")
+	callees := fs.String("callees", "", "print functions which are called by the function")
+	reaches := fs.String("reaches", "", "print functions of the packages which can reach the function")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] callgraph [-algo cha|vta] [-callers|-callees|-reaches <pkg.Func>] [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if algo == knife.CallGraphNone {
+		return fmt.Errorf("callgraph: -algo must be cha or vta")
+	}
+
+	cgOpt := *opt
+	cgOpt.CallGraph = algo
+	k, err := newKnife(&cgOpt, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	cg := k.CallGraph()
+	prog := k.Program()
+
+	lookup := func(name string) (knife.Object, error) {
+		obj := prog.Lookup(name)
+		if _, ok := obj.(*knife.Func); !ok {
+			return nil, fmt.Errorf("callgraph: function %s is not found in the packages and their dependencies", name)
+		}
+		return obj, nil
+	}
+
+	var (
+		data  any = cg
+		funcs []*knife.Func
+		list  bool
+	)
+	switch {
+	case *callers != "":
+		f, err := lookup(*callers)
+		if err != nil {
+			return err
+		}
+		funcs, list = cg.Callers(f), true
+	case *callees != "":
+		f, err := lookup(*callees)
+		if err != nil {
+			return err
+		}
+		funcs, list = cg.Callees(f), true
+	case *reaches != "":
+		to, err := lookup(*reaches)
+		if err != nil {
+			return err
+		}
+		for _, f := range packageFuncs(prog, cg) {
+			if cg.Reaches(f, to) {
+				funcs = append(funcs, f)
+			}
+		}
+		list = true
+	}
+
+	if list {
+		data = funcs
+	}
+
+	tmpl, err := templateFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		execOpt, err := executeOptionFromFlags()
+		if err != nil {
+			return err
+		}
+		return k.ExecuteData(os.Stdout, data, tmpl, execOpt)
+	}
+
+	if list {
+		return printFuncs(os.Stdout, funcs)
+	}
+	return printCallGraph(os.Stdout, prog, cg)
+}
+
+// packageFuncs returns functions in the call graph which are declared in the packages of the program.
+func packageFuncs(prog *knife.Program, cg *knife.CallGraph) []*knife.Func {
+	var funcs []*knife.Func
+	for _, f := range cg.Funcs() {
+		if pkg := f.TypesFunc.Pkg(); pkg != nil && prog.Package(pkg.Path()) != nil {
+			funcs = append(funcs, f)
+		}
+	}
+	return funcs
+}
+
+func printFuncs(w io.Writer, funcs []*knife.Func) error {
+	for _, f := range funcs {
+		if _, err := fmt.Fprintln(w, f.TypesFunc.FullName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printCallGraph(w io.Writer, prog *knife.Program, cg *knife.CallGraph) error {
+	for _, caller := range packageFuncs(prog, cg) {
+		for _, callee := range cg.Callees(caller) {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", caller.TypesFunc.FullName(), callee.TypesFunc.FullName()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runExplainImpl explains why the type does not implement the interface.
+// It prints each missing method and each method with a wrong signature,
+// and it reports when only *T implements the interface.
+// If -f or -template is given, the template is executed with the missing methods.
+// The packages of the type and the interface must be loaded by the patterns
+// or imported by the loaded packages.
+//
+//	knife [flags] explain-impl <type> <iface> [patterns]
+func runExplainImpl(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("explain-impl", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] explain-impl <type> <iface> [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return fmt.Errorf("explain-impl: a type and an interface must be specified (e.g. explain-impl *bytes.Buffer io.ReadWriteCloser bytes)")
+	}
+
+	k, err := newKnife(opt, fs.Args()[2:])
+	if err != nil {
+		return err
+	}
+
+	prog := k.Program()
+	typName, ifaceName := fs.Arg(0), fs.Arg(1)
+
+	typ, err := lookupType(prog, typName)
+	if err != nil {
+		return fmt.Errorf("explain-impl: %w", err)
+	}
+
+	iface, err := lookupType(prog, ifaceName)
+	if err != nil {
+		return fmt.Errorf("explain-impl: %w", err)
+	}
+
+	if !types.IsInterface(iface) {
+		return fmt.Errorf("explain-impl: %s is not an interface type", ifaceName)
+	}
+
+	missing := knife.MissingMethods(typ, iface)
+
+	tmpl, err := templateFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		execOpt, err := executeOptionFromFlags()
+		if err != nil {
+			return err
+		}
+		return k.ExecuteData(os.Stdout, missing, tmpl, execOpt)
+	}
+
+	return printMissingMethods(os.Stdout, k, typ, iface, missing)
+}
+
+// lookupType returns a type which is specified by a qualified name such as "io.Reader"
+// or a pointer of it such as "*bytes.Buffer".
+func lookupType(prog *knife.Program, name string) (types.Type, error) {
+	base := strings.TrimPrefix(name, "*")
+	tn, ok := prog.Lookup(base).(*knife.TypeName)
+	if !ok || tn == nil {
+		return nil, fmt.Errorf("%s is not a type in the packages and their dependencies: its package must be loaded or imported", base)
+	}
+
+	typ := tn.TypesTypeName.Type()
+	if base != name {
+		typ = types.NewPointer(typ)
+	}
+	return typ, nil
+}
+
+func printMissingMethods(w io.Writer, k *knife.Knife, typ, iface types.Type, missing []*knife.MissingMethod) error {
+	pointerOnly := len(missing) > 0
+	for _, m := range missing {
+		pointerOnly = pointerOnly && m.Pointer && !m.WrongSignature
+	}
+
+	var err error
+	switch {
+	case len(missing) == 0:
+		_, err = fmt.Fprintf(w, "%s implements %s\n", typ, iface)
+	case pointerOnly:
+		_, err = fmt.Fprintf(w, "%s does not implement %s, but %s implements it\n", typ, iface, types.NewPointer(typ))
+	default:
+		_, err = fmt.Fprintf(w, "%s does not implement %s\n", typ, iface)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, m := range missing {
+		var pos any = m.Method
+		if m.Actual != nil {
+			pos = m.Actual
+		}
+
+		if _, err := fmt.Fprintf(w, "\t%s\t%s\n", k.Position(pos), m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
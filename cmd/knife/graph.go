@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runGraph prints a graph of the packages.
+//
+//	knife [flags] graph imports [-format dot|mermaid|json] [-module] [-depth N] [-collapse prefixes] [-cycles] [-highlight regexp] [patterns]
+func runGraph(opt *knife.KnifeOption, args []string) error {
+	if len(args) == 0 || args[0] != "imports" {
+		return fmt.Errorf("graph: a kind of graph must be specified (imports)")
+	}
+
+	fs := flag.NewFlagSet("graph imports", flag.ExitOnError)
+	var format knife.GraphFormat
+	fs.Var(&format, "format", "an output format (dot|mermaid|json)")
+	moduleOnly := fs.Bool("module", false, "exclude packages which are not in the modules of the packages")
+	depth := fs.Int("depth", 0, "limit the number of imports from the packages (0 means no limit)")
+	collapse := fs.String("collapse", "", "a comma-separated list of package path prefixes to collapse into a node")
+	cycles := fs.Bool("cycles", false, "highlight import cycles")
+	highlight := fs.String("highlight", "", "highlight edges whose \"from -> to\" matches the regexp")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] graph imports [-format dot|mermaid|json] [-module] [-depth N] [-collapse prefixes] [-cycles] [-highlight regexp] [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *depth < 0 {
+		return fmt.Errorf("graph: -depth must not be negative")
+	}
+
+	graphOpt := &knife.ImportGraphOption{
+		ModuleOnly: *moduleOnly,
+		Depth:      *depth,
+		Cycles:     *cycles,
+	}
+
+	if *collapse != "" {
+		for _, prefix := range strings.Split(*collapse, ",") {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				graphOpt.Collapse = append(graphOpt.Collapse, prefix)
+			}
+		}
+	}
+
+	if *highlight != "" {
+		re, err := regexp.Compile(*highlight)
+		if err != nil {
+			return fmt.Errorf("graph: invalid -highlight: %w", err)
+		}
+		graphOpt.Highlight = re
+	}
+
+	k, err := newKnife(opt, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return knife.NewImportGraph(k.KnifePackages(), graphOpt).Write(os.Stdout, format)
+}
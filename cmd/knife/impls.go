@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runImpls lists concrete types which implement the interface.
+//
+//	knife [flags] impls [-deps] <iface> [patterns]
+func runImpls(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("impls", flag.ExitOnError)
+	deps := fs.Bool("deps", false, "search dependencies of the packages too")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] impls [-deps] <iface> [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("impls: an interface must be specified (e.g. io.Reader)")
+	}
+
+	k, err := newKnife(opt, fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+	prog := k.Program()
+	obj := prog.Lookup(name)
+	if obj == nil {
+		return fmt.Errorf("impls: %s is not found in the packages and their dependencies", name)
+	}
+
+	if tn, ok := obj.(*knife.TypeName); !ok || !types.IsInterface(tn.TypesTypeName.Type()) {
+		return fmt.Errorf("impls: %s is not an interface type", name)
+	}
+
+	impls := knife.Implementers(prog.Packages, obj, *deps)
+	return printImpls(os.Stdout, k, impls)
+}
+
+func printImpls(w io.Writer, k *knife.Knife, impls []*knife.Implementation) error {
+	for _, impl := range impls {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", impl.TypeString(), impl.Receiver(), k.Position(impl.Type)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runLayers checks imports of the packages with the layer rules.
+// It reports each violating import and fails if there are violations.
+//
+//	knife [flags] layers [-rules layers.json] [-format plain|json|sarif] [patterns]
+func runLayers(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("layers", flag.ExitOnError)
+	rulesFile := fs.String("rules", "layers.json", "a JSON file of layers and rules")
+	var format knife.ReportFormat
+	fs.Var(&format, "format", "an output format (plain|json|sarif)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] layers [-rules layers.json] [-format plain|json|sarif] [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := os.Open(*rulesFile)
+	if err != nil {
+		return fmt.Errorf("layers: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := knife.ReadLayerRules(f)
+	if err != nil {
+		return fmt.Errorf("layers: %s: %w", *rulesFile, err)
+	}
+
+	k, err := newKnife(opt, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	vs := k.CheckLayers(rules)
+	diags := make([]*knife.Diagnostic, len(vs))
+	for i, v := range vs {
+		diags[i] = &knife.Diagnostic{
+			Pos:     k.Position(v),
+			Rule:    "layers",
+			Message: v.String(),
+		}
+	}
+
+	if err := knife.WriteDiagnostics(os.Stdout, "knife", diags, format); err != nil {
+		return err
+	}
+
+	if len(vs) > 0 {
+		return fmt.Errorf("layers: %d violation(s)", len(vs))
+	}
+
+	return nil
+}
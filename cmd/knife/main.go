@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,6 +22,17 @@ var (
 	flagExtraData string
 	flagXPath     string
 	flagTests     bool
+	flagStrict    bool
+	flagVariants  knife.VariantPolicy
+	flagLoad      knife.LoadStrategy
+	flagBuild     knife.BuildContext
+	flagPlatforms string
+	flagReport    bool
+	flagAll       bool
+	flagCallGraph knife.CallGraphAlgorithm
+	flagJSON      bool
+	flagJSONDepth int
+	flagDiag      knife.ReportFormat
 )
 
 func init() {
@@ -30,6 +42,17 @@ func init() {
 	flag.StringVar(&flagExtraData, "data", "", "extra data (key:value,key:value)")
 	flag.StringVar(&flagXPath, "xpath", "", "A XPath expression for an AST node")
 	flag.BoolVar(&flagTests, "tests", true, "include test files")
+	flag.BoolVar(&flagStrict, "strict", os.Getenv("CI") != "", "fail on load, parse and type errors (enabled by default if $CI is set)")
+	flag.Var(&flagVariants, "variants", "a policy for test variants of packages (collapse|all)")
+	flag.Var(&flagLoad, "load", "a strategy for loading packages (all|root)")
+	flagBuild.RegisterFlags(flag.CommandLine)
+	flag.StringVar(&flagPlatforms, "platforms", "", "a comma-separated list of GOOS/GOARCH pairs to load (e.g. linux/amd64,windows/amd64)")
+	flag.BoolVar(&flagReport, "platform-report", false, "print objects which exist on only some of the platforms")
+	flag.BoolVar(&flagAll, "all", false, "execute the template once with all packages")
+	flag.Var(&flagCallGraph, "callgraph", "an algorithm to build a call graph for callers, callees and reaches (none|cha|vta)")
+	flag.BoolVar(&flagJSON, "json", false, "print packages as JSON instead of the format (see knife.schema.json)")
+	flag.IntVar(&flagJSONDepth, "json-depth", 0, "a limit of nesting of packages, objects and named types in JSON (0 means no limit)")
+	flag.Var(&flagDiag, "diag", "an output format of diagnostics which are reported by diag and report in the template (plain|json|sarif)")
 	flag.Parse()
 }
 
@@ -52,33 +75,90 @@ func run(ctx context.Context, args []string) error {
 	}
 
 	knifeOpt := &knife.KnifeOption{
-		Tests: flagTests,
+		Tests:        flagTests,
+		Strict:       flagStrict,
+		Variants:     flagVariants,
+		Load:         flagLoad,
+		BuildContext: flagBuild,
+		CallGraph:    flagCallGraph,
 	}
-	k, err := knife.New(knifeOpt, args...)
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "impls":
+			return runImpls(knifeOpt, args[1:])
+		case "refs":
+			return runRefs(knifeOpt, args[1:])
+		case "callgraph":
+			return runCallGraph(knifeOpt, args[1:])
+		case "graph":
+			return runGraph(knifeOpt, args[1:])
+		case "why":
+			return runWhy(knifeOpt, args[1:])
+		case "layers":
+			return runLayers(knifeOpt, args[1:])
+		case "apidiff":
+			return runAPIDiff(knifeOpt, args[1:])
+		case "api":
+			return runAPI(knifeOpt, args[1:])
+		case "rewrite":
+			return runRewrite(knifeOpt, args[1:])
+		case "explain-impl":
+			return runExplainImpl(knifeOpt, args[1:])
+		}
+	}
+	k, err := newKnife(knifeOpt, args)
 	if err != nil {
 		return err
 	}
 
+	if flagReport {
+		return knife.PlatformReport(os.Stdout, k.Platforms(), k.KnifePackages())
+	}
+
 	var w io.Writer = os.Stdout
 
-	opt := &knife.ExecuteOption{
-		XPath: flagXPath,
+	opt, err := executeOptionFromFlags()
+	if err != nil {
+		return err
 	}
 
-	if flagExtraData != "" {
-		extraData, err := parseExtraData(flagExtraData)
-		if err != nil {
+	tmpl, err := readTemplate()
+	if err != nil {
+		return err
+	}
+
+	var diags []*knife.Diagnostic
+	opt.Report = func(d *knife.Diagnostic) {
+		d.Rule = ruleName()
+		diags = append(diags, d)
+	}
+
+	// structured diagnostics must not be mixed with the output of the template
+	if flagDiag != knife.ReportPlain {
+		w = io.Discard
+	}
+
+	if err := execute(k, w, tmpl, opt); err != nil {
+		return err
+	}
+
+	if len(diags) > 0 || flagDiag != knife.ReportPlain {
+		if err := knife.WriteDiagnostics(os.Stdout, "knife", diags, flagDiag); err != nil {
 			return err
 		}
-		opt.ExtraData = extraData
 	}
 
-	var tmpl any = flagFormat
-	if flagTemplate != "" {
-		tmpl, err = os.ReadFile(flagTemplate)
-		if err != nil {
-			return fmt.Errorf("cannot read template: %w", err)
-		}
+	if len(diags) > 0 {
+		return fmt.Errorf("%d diagnostic(s)", len(diags))
+	}
+
+	return nil
+}
+
+func execute(k *knife.Knife, w io.Writer, tmpl any, opt *knife.ExecuteOption) error {
+	if flagAll {
+		return k.ExecuteAll(w, tmpl, opt)
 	}
 
 	pkgs := k.Packages()
@@ -96,6 +176,81 @@ func run(ctx context.Context, args []string) error {
 	return nil
 }
 
+// ruleName returns a rule ID of diagnostics which is the name of the template file
+// such as "nolongfuncs" for nolongfuncs.tmpl. It is "knife" for -f.
+func ruleName() string {
+	if flagTemplate == "" {
+		return "knife"
+	}
+	base := filepath.Base(flagTemplate)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func newKnife(opt *knife.KnifeOption, patterns []string) (*knife.Knife, error) {
+	if flagPlatforms == "" {
+		if flagReport {
+			return nil, fmt.Errorf("-platform-report requires -platforms")
+		}
+		return knife.New(opt, patterns...)
+	}
+
+	platforms, err := knife.ParsePlatforms(flagPlatforms)
+	if err != nil {
+		return nil, err
+	}
+
+	return knife.NewMatrix(opt, platforms, patterns...)
+}
+
+func executeOptionFromFlags() (*knife.ExecuteOption, error) {
+	opt := &knife.ExecuteOption{
+		XPath: flagXPath,
+	}
+
+	if flagExtraData != "" {
+		extraData, err := parseExtraData(flagExtraData)
+		if err != nil {
+			return nil, err
+		}
+		opt.ExtraData = extraData
+	}
+
+	return opt, nil
+}
+
+func readTemplate() (any, error) {
+	if flagJSON {
+		return fmt.Sprintf("{{json . %d}}\n", flagJSONDepth), nil
+	}
+
+	if flagTemplate == "" {
+		return flagFormat, nil
+	}
+
+	tmpl, err := os.ReadFile(flagTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateFromFlags returns a template if -f, -template or -json is given explicitly.
+// Otherwise it returns nil.
+func templateFromFlags() (any, error) {
+	var set bool
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "f" || f.Name == "template" || f.Name == "json" {
+			set = true
+		}
+	})
+
+	if !set {
+		return nil, nil
+	}
+
+	return readTemplate()
+}
+
 func parseExtraData(extraData string) (map[string]any, error) {
 	m := map[string]any{}
 	kvs := strings.Split(extraData, ",")
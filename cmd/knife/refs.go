@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runRefs lists references to the object.
+// If -f or -template is given, the template is executed with the references.
+//
+//	knife [flags] refs <pkg.Name[.Field|.Method]> [patterns]
+func runRefs(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("refs", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] refs <pkg.Name[.Field|.Method]> [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("refs: an object must be specified (e.g. net/http.Request.URL)")
+	}
+
+	k, err := newKnife(opt, fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+	obj := k.Program().Lookup(name)
+	if obj == nil {
+		return fmt.Errorf("refs: %s is not found in the packages and their dependencies", name)
+	}
+
+	refs := k.Refs(obj)
+
+	tmpl, err := templateFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		execOpt, err := executeOptionFromFlags()
+		if err != nil {
+			return err
+		}
+		return k.ExecuteData(os.Stdout, refs, tmpl, execOpt)
+	}
+
+	return printRefs(os.Stdout, k, refs)
+}
+
+func printRefs(w io.Writer, k *knife.Knife, refs []*knife.Ref) error {
+	for _, ref := range refs {
+		fn := "-"
+		if ref.Func != nil {
+			fn = ref.Func.TypesFunc.FullName()
+		}
+
+		kind := string(ref.Kind)
+		if ref.Implicit {
+			kind += "(implicit)"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", k.Position(ref), kind, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runRewrite executes the template and applies edits which are proposed by
+// replace, insertBefore and delete in the template to the source files.
+// The edited files are formatted with gofmt. Without -w and -diff,
+// it prints the edited files.
+//
+//	knife [flags] rewrite [-diff] [-w] [patterns]
+func runRewrite(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("rewrite", flag.ExitOnError)
+	write := fs.Bool("w", false, "write the edited files")
+	diff := fs.Bool("diff", false, "print unified diffs of the edited files")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] rewrite [-diff] [-w] [patterns]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k, err := newKnife(opt, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	execOpt, err := executeOptionFromFlags()
+	if err != nil {
+		return err
+	}
+
+	var edits []*knife.Edit
+	execOpt.Edit = func(e *knife.Edit) {
+		edits = append(edits, e)
+	}
+
+	tmpl, err := readTemplate()
+	if err != nil {
+		return err
+	}
+
+	// only edits are used
+	if err := execute(k, io.Discard, tmpl, execOpt); err != nil {
+		return err
+	}
+
+	if len(edits) == 0 {
+		return nil
+	}
+
+	pkgs := k.Packages()
+	files, err := knife.ApplyEdits(pkgs[0].Fset, opt.Overlay, edits)
+	if err != nil {
+		return fmt.Errorf("rewrite: %w", err)
+	}
+
+	// files of dependencies such as the standard library and files
+	// which are generated by cgo must not be rewritten
+	loaded := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, name := range pkg.GoFiles {
+			loaded[name] = true
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if !loaded[name] {
+			return fmt.Errorf("rewrite: %s is not a file of the packages", name)
+		}
+
+		// the edited contents are based on the overlay but the file on the disk is different
+		if _, ok := opt.Overlay[name]; ok && *write {
+			return fmt.Errorf("rewrite: %s is replaced by the overlay and cannot be written", name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		src := files[name]
+		switch {
+		case *diff:
+			old, err := knife.ReadSource(name, opt.Overlay)
+			if err != nil {
+				return fmt.Errorf("rewrite: %w", err)
+			}
+			fmt.Print(knife.UnifiedDiff(name, old, src))
+		case !*write:
+			fmt.Print(string(src))
+		}
+
+		if *write {
+			info, err := os.Stat(name)
+			if err != nil {
+				return fmt.Errorf("rewrite: %w", err)
+			}
+
+			if err := os.WriteFile(name, src, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("rewrite: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gostaticanalysis/knife"
+)
+
+// runWhy prints all shortest import paths from the packages to the package
+// with the import specs which are responsible for each import.
+// If -f or -template is given, the template is executed with the paths.
+//
+//	knife [flags] why <from-pattern> <to-pkg>
+func runWhy(opt *knife.KnifeOption, args []string) error {
+	fs := flag.NewFlagSet("why", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: knife [flags] why <from-pattern> <to-pkg>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return fmt.Errorf("why: a pattern and a package must be specified (e.g. ./cmd/app net/http)")
+	}
+
+	patterns, to := fs.Args()[:fs.NArg()-1], fs.Arg(fs.NArg()-1)
+	k, err := newKnife(opt, patterns)
+	if err != nil {
+		return err
+	}
+
+	paths := k.Why(to)
+
+	tmpl, err := templateFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		execOpt, err := executeOptionFromFlags()
+		if err != nil {
+			return err
+		}
+		return k.ExecuteData(os.Stdout, paths, tmpl, execOpt)
+	}
+
+	if len(paths) == 0 {
+		return fmt.Errorf("why: %s is not imported by the packages", to)
+	}
+
+	return printImportPaths(os.Stdout, k, paths)
+}
+
+func printImportPaths(w io.Writer, k *knife.Knife, paths []*knife.ImportPath) error {
+	for i, path := range paths {
+		if i != 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintln(w, path); err != nil {
+			return err
+		}
+
+		for _, step := range path.Steps {
+			if _, err := fmt.Fprintf(w, "\t%s\t%s\n", k.Position(step), step); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
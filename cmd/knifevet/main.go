@@ -0,0 +1,13 @@
+// knifevet runs a knife template as an analyzer.
+//
+//	knifevet -template rule.tmpl ./...
+//	go vet -vettool=$(which knifevet) -template=$PWD/rule.tmpl ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/gostaticanalysis/knife/analyzer"
+)
+
+func main() { singlechecker.Main(analyzer.Analyzer) }
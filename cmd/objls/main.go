@@ -19,12 +19,14 @@ var (
 	flagFilter   string
 	flagExported bool
 	flagPos      bool
+	flagBuild    knife.BuildContext
 )
 
 func init() {
 	flag.StringVar(&flagFilter, "f", "all", "object filter(all|const|func|var)")
 	flag.BoolVar(&flagExported, "exported", true, "filter only exported object")
 	flag.BoolVar(&flagPos, "pos", false, "print position")
+	flagBuild.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 }
 
@@ -36,7 +38,10 @@ func main() {
 }
 
 func run(ctx context.Context, args []string) error {
-	cutterOpt := &cutter.CutterOption{Tests: true}
+	cutterOpt := &cutter.CutterOption{
+		Tests:        true,
+		BuildContext: flagBuild,
+	}
 	c, err := cutter.New(cutterOpt, args...)
 	if err != nil {
 		return err
@@ -49,7 +54,7 @@ func run(ctx context.Context, args []string) error {
 		var buf bytes.Buffer
 		readers[i] = &buf
 
-		if len(pkg.Types) == 0 {
+		if len(pkg.Types()) == 0 {
 			continue
 		}
 
@@ -24,13 +24,15 @@ var (
 	flagImplements string
 	flagExported   bool
 	flagPos        bool
+	flagBuild      knife.BuildContext
 )
 
 func init() {
-	flag.StringVar(&flagKind, "kind", "all", "all|interface|func|struct|chan|array|slice|map")
+	flag.StringVar(&flagKind, "kind", "all", "all|interface|func|struct|chan|array|slice|map|alias")
 	flag.StringVar(&flagImplements, "implements", "", "implements interface")
 	flag.BoolVar(&flagExported, "exported", true, "filter only exported types")
 	flag.BoolVar(&flagPos, "pos", false, "print position")
+	flagBuild.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 }
 
@@ -42,7 +44,10 @@ func main() {
 }
 
 func run(ctx context.Context, args []string) error {
-	cutterOpt := &cutter.CutterOption{Tests: true}
+	cutterOpt := &cutter.CutterOption{
+		Tests:        true,
+		BuildContext: flagBuild,
+	}
 	c, err := cutter.New(cutterOpt, args...)
 	if err != nil {
 		return err
@@ -55,13 +60,13 @@ func run(ctx context.Context, args []string) error {
 		var buf bytes.Buffer
 		readers[i] = &buf
 
-		if len(pkg.Types) == 0 {
+		if len(pkg.Types()) == 0 {
 			continue
 		}
 
 		g.Add(func(ctx context.Context) error {
 
-			for name, typ := range pkg.Types {
+			for name, typ := range pkg.Types() {
 				if !match(typ) {
 					continue
 				}
@@ -125,6 +130,8 @@ func matchKind(typ *knife.TypeName) bool {
 		return knife.ToSlice(typ) != nil
 	case "map":
 		return knife.ToMap(typ) != nil
+	case "alias":
+		return typ.IsAlias
 	default:
 		return true
 	}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"go/token"
 	"io"
+	"slices"
+	"sync"
 
 	"golang.org/x/tools/go/packages"
 
@@ -16,6 +18,19 @@ import (
 // CutterOption is an option for New.
 type CutterOption struct {
 	Tests bool
+	// Strict reports load, parse and type errors of the packages
+	// and their dependencies as a [*knife.LoadError].
+	// If it is false, the errors are set to Errors field of [knife.Package].
+	Strict bool
+	// Variants is a policy for test variants of packages.
+	Variants knife.VariantPolicy
+	knife.BuildContext
+	// Fset is a file set which is used to load packages.
+	// If it is nil, a new file set is created.
+	Fset *token.FileSet
+	// Universe is used to create knife objects.
+	// If it is nil, a new universe is created and owned by the [Cutter].
+	Universe *knife.Universe
 }
 
 // Cutter is a lightweight version of Knife which is resterected for type information.
@@ -23,6 +38,10 @@ type Cutter struct {
 	fset      *token.FileSet
 	pkgs      []*packages.Package
 	knifePkgs []*knife.Package
+	platforms []knife.Platform
+	u         *knife.Universe
+	progOnce  sync.Once
+	prog      *knife.Program
 }
 
 // New creates a [Cutter].
@@ -30,11 +49,11 @@ func New(opt *CutterOption, patterns ...string) (*Cutter, error) {
 	if opt == nil {
 		opt = &CutterOption{Tests: true}
 	}
-	mode := packages.NeedName | packages.NeedTypes
-	cfg := &packages.Config{
-		Fset:  token.NewFileSet(),
-		Mode:  mode,
-		Tests: opt.Tests,
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedForTest | packages.NeedModule
+	cfg := opt.PackagesConfig(mode, opt.Tests)
+	cfg.Fset = opt.Fset
+	if cfg.Fset == nil {
+		cfg.Fset = token.NewFileSet()
 	}
 
 	pkgs, err := packages.Load(cfg, patterns...)
@@ -42,11 +61,25 @@ func New(opt *CutterOption, patterns ...string) (*Cutter, error) {
 		return nil, fmt.Errorf("load: %w", err)
 	}
 
+	pkgs = knife.FilterVariants(pkgs, opt.Variants)
+
+	if opt.Strict {
+		if err := knife.CheckErrors(pkgs); err != nil {
+			return nil, err
+		}
+	}
+
+	u := opt.Universe
+	if u == nil {
+		u = knife.NewUniverse()
+	}
+
 	knifePkgs := make([]*knife.Package, len(pkgs))
 	var g gogroup.Group
 	for i := range pkgs {
 		g.Add(func(ctx context.Context) error {
-			knifePkgs[i] = knife.NewPackage(pkgs[i].Types)
+			knifePkgs[i] = u.Package(pkgs[i].Types)
+			knife.SetPackageInfo(knifePkgs[i], pkgs[i])
 			return nil
 		})
 	}
@@ -59,10 +92,75 @@ func New(opt *CutterOption, patterns ...string) (*Cutter, error) {
 		fset:      cfg.Fset,
 		pkgs:      pkgs,
 		knifePkgs: knifePkgs,
+		u:         u,
+	}, nil
+}
+
+// NewMatrix loads the packages for each platform and creates a [Cutter]
+// which holds merged packages.
+// See [knife.MergePlatforms] for details of merging.
+func NewMatrix(opt *CutterOption, platforms []knife.Platform, patterns ...string) (*Cutter, error) {
+	if opt == nil {
+		opt = &CutterOption{Tests: true}
+	}
+
+	fset := opt.Fset
+	if fset == nil {
+		fset = token.NewFileSet()
+	}
+
+	u := opt.Universe
+	if u == nil {
+		u = knife.NewUniverse()
+	}
+
+	cutters := make([]*Cutter, len(platforms))
+	knifePkgs := make([][]*knife.Package, len(platforms))
+	for i, p := range platforms {
+		popt := *opt
+		popt.Fset = fset
+		popt.Universe = u
+		popt.GOOS, popt.GOARCH = p.GOOS, p.GOARCH
+		c, err := New(&popt, patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		cutters[i] = c
+		knifePkgs[i] = c.knifePkgs
+	}
+
+	merged := knife.MergePlatforms(platforms, knifePkgs)
+
+	// the first loaded package is a representative of the merged package
+	pkgs := make([]*packages.Package, 0, len(merged))
+	for _, pkg := range merged {
+		for _, c := range cutters {
+			i := slices.IndexFunc(c.pkgs, func(p *packages.Package) bool {
+				return p.Types == pkg.TypesPackage
+			})
+			if i != -1 {
+				pkgs = append(pkgs, c.pkgs[i])
+				break
+			}
+		}
+	}
+
+	return &Cutter{
+		fset:      fset,
+		pkgs:      pkgs,
+		knifePkgs: merged,
+		platforms: platforms,
+		u:         u,
 	}, nil
 }
 
+// Platforms returns platforms which are given to [NewMatrix].
+func (c *Cutter) Platforms() []knife.Platform {
+	return c.platforms
+}
+
 // Packages returns packages.
+// A package which is merged by [NewMatrix] is the one loaded for the first platform.
 func (c *Cutter) Packages() []*packages.Package {
 	return c.pkgs
 }
@@ -81,6 +179,14 @@ func (c *Cutter) KnifePackages() []*knife.Package {
 	return c.knifePkgs
 }
 
+// Program returns a [knife.Program] which has all packages.
+func (c *Cutter) Program() *knife.Program {
+	c.progOnce.Do(func() {
+		c.prog = knife.NewProgram(c.knifePkgs)
+	})
+	return c.prog
+}
+
 // Option is a option of Execute.
 type Option struct {
 	ExtraData map[string]any
@@ -89,26 +195,17 @@ type Option struct {
 // Execute outputs the pkg with the format.
 func (c *Cutter) Execute(w io.Writer, pkg *knife.Package, tmpl any, opt *Option) error {
 
-	var tmplStr string
-	switch tmpl := tmpl.(type) {
-	case string:
-		tmplStr = tmpl
-	case []byte:
-		tmplStr = string(tmpl)
-	case io.Reader:
-		b, err := io.ReadAll(tmpl)
-		if err != nil {
-			return fmt.Errorf("cannnot read template: %w", err)
-		}
-		tmplStr = string(b)
-	default:
-		return fmt.Errorf("template must be string, []byte or io.Reader: %T", tmpl)
+	tmplStr, err := knife.ReadTemplate(tmpl)
+	if err != nil {
+		return err
 	}
 
 	td := &knife.TempalteData{
-		Fset:  c.fset,
-		Pkg:   pkg.TypesPackage,
-		Extra: opt.ExtraData,
+		Fset:     c.fset,
+		Pkg:      pkg.TypesPackage,
+		Extra:    opt.ExtraData,
+		Universe: c.u,
+		Program:  c.Program(),
 	}
 	t, err := knife.NewTemplate(td).Parse(tmplStr)
 	if err != nil {
@@ -121,3 +218,34 @@ func (c *Cutter) Execute(w io.Writer, pkg *knife.Package, tmpl any, opt *Option)
 
 	return nil
 }
+
+// ExecuteAll outputs all packages with the format at once.
+// The template is given a [*knife.Program] which has all packages.
+func (c *Cutter) ExecuteAll(w io.Writer, tmpl any, opt *Option) error {
+	if opt == nil {
+		opt = &Option{}
+	}
+
+	tmplStr, err := knife.ReadTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+
+	prog := c.Program()
+	td := &knife.TempalteData{
+		Fset:     c.fset,
+		Extra:    opt.ExtraData,
+		Universe: c.u,
+		Program:  prog,
+	}
+	t, err := knife.NewTemplate(td).Parse(tmplStr)
+	if err != nil {
+		return fmt.Errorf("template parse: %w", err)
+	}
+
+	if err := t.Execute(w, prog); err != nil {
+		return fmt.Errorf("template execute: %w", err)
+	}
+
+	return nil
+}
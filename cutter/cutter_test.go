@@ -3,6 +3,7 @@ package cutter_test
 import (
 	"testing"
 
+	"github.com/gostaticanalysis/knife"
 	"github.com/gostaticanalysis/knife/cutter"
 )
 
@@ -17,3 +18,28 @@ func TestNew(t *testing.T) {
 		t.Error("cutter.New must creates knife.Package")
 	}
 }
+
+func TestNewMatrix(t *testing.T) {
+	platforms := []knife.Platform{
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "windows", GOARCH: "amd64"},
+	}
+	c, err := cutter.NewMatrix(&cutter.CutterOption{}, platforms, "../testdata/buildctx")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	pkgs := c.KnifePackages()
+	if len(pkgs) != 1 {
+		t.Fatalf("the number of packages must be 1 but %d", len(pkgs))
+	}
+
+	// a package which is loaded for each platform is returned once
+	if got := c.Packages(); len(got) != 1 || got[0].Types != pkgs[0].TypesPackage {
+		t.Errorf("Packages must return the package of the first platform: %v", got)
+	}
+
+	if got := c.Platforms(); len(got) != len(platforms) {
+		t.Errorf("the number of platforms must be %d but %d", len(platforms), len(got))
+	}
+}
@@ -0,0 +1,107 @@
+package knife
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Error is an error which occurred while loading a package.
+type Error struct {
+	// Kind is one of "list", "parse", "type" or "unknown".
+	Kind string `json:"kind"`
+	// Pos is a position of the error in "file:line:col" format.
+	// It is empty if the position is unknown.
+	Pos string `json:"pos,omitempty"`
+	Msg string `json:"msg"`
+}
+
+var _ error = (*Error)(nil)
+
+func (e *Error) Error() string {
+	if e.Pos == "" {
+		return e.Msg
+	}
+	return e.Pos + ": " + e.Msg
+}
+
+// PackageErrors returns list, parse and type errors of the package.
+func PackageErrors(pkg *packages.Package) []*Error {
+	if pkg == nil || len(pkg.Errors) == 0 {
+		return nil
+	}
+
+	errs := make([]*Error, len(pkg.Errors))
+	for i, err := range pkg.Errors {
+		e := &Error{
+			Pos: err.Pos,
+			Msg: err.Msg,
+		}
+
+		if e.Pos == "-" {
+			e.Pos = ""
+		}
+
+		switch err.Kind {
+		case packages.ListError:
+			e.Kind = "list"
+		case packages.ParseError:
+			e.Kind = "parse"
+		case packages.TypeError:
+			e.Kind = "type"
+		default:
+			e.Kind = "unknown"
+		}
+
+		errs[i] = e
+	}
+
+	return errs
+}
+
+// LoadError is an error which reports errors of loaded packages.
+type LoadError struct {
+	Packages []*PackageErrorList
+}
+
+// PackageErrorList is a list of errors of a package.
+type PackageErrorList struct {
+	Path   string   `json:"path"`
+	Errors []*Error `json:"errors"`
+}
+
+var _ error = (*LoadError)(nil)
+
+func (e *LoadError) Error() string {
+	var sb strings.Builder
+	for _, pkg := range e.Packages {
+		for _, err := range pkg.Errors {
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			fmt.Fprintf(&sb, "%s: %s", pkg.Path, err)
+		}
+	}
+	return sb.String()
+}
+
+// CheckErrors returns a [*LoadError] if the packages or their dependencies have errors.
+// It returns nil if there are no errors.
+func CheckErrors(pkgs []*packages.Package) error {
+	var lerr LoadError
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if errs := PackageErrors(pkg); len(errs) > 0 {
+			lerr.Packages = append(lerr.Packages, &PackageErrorList{
+				Path:   pkg.PkgPath,
+				Errors: errs,
+			})
+		}
+	})
+
+	if len(lerr.Packages) == 0 {
+		return nil
+	}
+
+	return &lerr
+}
@@ -0,0 +1,51 @@
+package knife
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNew_Errors(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		_, err := New(&KnifeOption{Strict: true}, "./testdata/broken")
+		var lerr *LoadError
+		if !errors.As(err, &lerr) {
+			t.Fatalf("knife.New must return *knife.LoadError but %v", err)
+		}
+
+		if len(lerr.Packages) != 1 || len(lerr.Packages[0].Errors) != 1 {
+			t.Fatalf("unexpected errors: %v", lerr)
+		}
+
+		got := lerr.Packages[0].Errors[0]
+		if got.Kind != "type" {
+			t.Errorf("kind must be type but %q", got.Kind)
+		}
+
+		if !strings.HasSuffix(got.Pos, "broken.go:4:9") {
+			t.Errorf("unexpected position: %q", got.Pos)
+		}
+	})
+
+	t.Run("tolerant", func(t *testing.T) {
+		k := newTestKnife(t, &KnifeOption{}, "./testdata/broken")
+
+		var buf strings.Builder
+		tmpl := `{{.IllTyped}}{{range .Errors}} {{.Kind}}{{end}}`
+		if err := k.Execute(&buf, k.Packages()[0], tmpl, &ExecuteOption{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if got, want := buf.String(), "true type"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		_, err := New(&KnifeOption{Strict: true}, "./testdata/buildctx")
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	})
+}
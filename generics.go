@@ -0,0 +1,408 @@
+package knife
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// TypeParam is a type parameter of a generic type or a generic function.
+type TypeParam struct {
+	TypesTypeParam *types.TypeParam
+	Name           string
+	// Index is an index of the type parameter in its type parameter list.
+	Index      int
+	Constraint *Type
+	// Core is the core type of the constraint.
+	// It is nil if the constraint does not have a core type such as any.
+	Core *Type
+}
+
+var _ fmt.Stringer = (*TypeParam)(nil)
+
+// NewTypeParam creates a [TypeParam] in a new [Universe].
+func NewTypeParam(tp *types.TypeParam) *TypeParam {
+	return NewUniverse().TypeParam(tp)
+}
+
+// TypeParam returns a [TypeParam] of tp in the universe.
+func (u *Universe) TypeParam(tp *types.TypeParam) *TypeParam {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newTypeParam(tp)
+}
+
+func (u *Universe) newTypeParam(tp *types.TypeParam) *TypeParam {
+	if tp == nil {
+		return nil
+	}
+
+	return load(u, tp, func(ntp *TypeParam) {
+		ntp.TypesTypeParam = tp
+		ntp.Name = tp.Obj().Name()
+		ntp.Index = tp.Index()
+		ntp.Constraint = u.newType(tp.Constraint())
+		ntp.Core = u.newType(coreType(tp))
+	})
+}
+
+func (u *Universe) newTypeParams(list *types.TypeParamList) []*TypeParam {
+	tps := make([]*TypeParam, list.Len())
+	for i := range tps {
+		tps[i] = u.newTypeParam(list.At(i))
+	}
+	return tps
+}
+
+func (u *Universe) newTypeArgs(list *types.TypeList) []*Type {
+	args := make([]*Type, list.Len())
+	for i := range args {
+		args[i] = u.newType(list.At(i))
+	}
+	return args
+}
+
+// ToTypeParam converts a type to a [TypeParam].
+// It returns nil if the type is not a type parameter.
+func ToTypeParam(t any) *TypeParam {
+	switch t := t.(type) {
+	case *Type:
+		return t.TypeParam()
+	case *TypeName:
+		return t.Type.TypeParam()
+	case types.Type:
+		return NewType(t).TypeParam()
+	}
+	return nil
+}
+
+// String returns the name of the type parameter such as "T".
+func (tp *TypeParam) String() string {
+	return tp.TypesTypeParam.String()
+}
+
+// Union is a union of terms such as ~int | ~string in a constraint.
+type Union struct {
+	TypesUnion *types.Union
+	Terms      []*Term
+}
+
+var _ fmt.Stringer = (*Union)(nil)
+
+// NewUnion creates a [Union] in a new [Universe].
+func NewUnion(un *types.Union) *Union {
+	return NewUniverse().Union(un)
+}
+
+// Union returns a [Union] of un in the universe.
+func (u *Universe) Union(un *types.Union) *Union {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newUnion(un)
+}
+
+func (u *Universe) newUnion(un *types.Union) *Union {
+	if un == nil {
+		return nil
+	}
+
+	return load(u, un, func(nun *Union) {
+		nun.TypesUnion = un
+		nun.Terms = make([]*Term, un.Len())
+		for i := range nun.Terms {
+			nun.Terms[i] = u.newTerm(un.Term(i))
+		}
+	})
+}
+
+// ToUnion converts a type to a [Union].
+// A constraint interface which embeds only one union such as
+// interface{ ~int | ~string } is converted to the union.
+func ToUnion(t any) *Union {
+	switch t := t.(type) {
+	case *Type:
+		return t.Union()
+	case *TypeName:
+		return t.Type.Union()
+	case *TypeParam:
+		return t.Constraint.Union()
+	case types.Type:
+		return NewType(t).Union()
+	}
+	return nil
+}
+
+func (un *Union) String() string {
+	return un.TypesUnion.String()
+}
+
+// Term is a term of a [Union] such as ~int.
+type Term struct {
+	TypesTerm *types.Term
+	// Tilde reports whether the term has ~, which means all types whose underlying type is Type.
+	Tilde bool
+	Type  *Type
+}
+
+var _ fmt.Stringer = (*Term)(nil)
+
+func (u *Universe) newTerm(t *types.Term) *Term {
+	if t == nil {
+		return nil
+	}
+
+	return load(u, t, func(nt *Term) {
+		nt.TypesTerm = t
+		nt.Tilde = t.Tilde()
+		nt.Type = u.newType(t.Type())
+	})
+}
+
+func (t *Term) String() string {
+	return t.TypesTerm.String()
+}
+
+// Instantiate instantiates a generic type or a generic function t with the type arguments
+// such as Cache[string, int]. Methods, fields and the signature of the instance are
+// substituted with the type arguments. It returns an error if t is not generic or
+// the type arguments do not satisfy the constraints.
+func (u *Universe) Instantiate(t any, targs ...any) (*Type, error) {
+	orig := typeOfValue(t)
+	switch typ := orig.(type) {
+	case *types.Named:
+		if typ.TypeParams().Len() == 0 || typ.TypeArgs().Len() != 0 {
+			return nil, fmt.Errorf("cannot instantiate %v: not a generic type", typ)
+		}
+	case *types.Alias:
+		if typ.TypeParams().Len() == 0 || typ.TypeArgs().Len() != 0 {
+			return nil, fmt.Errorf("cannot instantiate %v: not a generic type", typ)
+		}
+	case *types.Signature:
+		if typ.TypeParams().Len() == 0 {
+			return nil, fmt.Errorf("cannot instantiate %v: not a generic function", typ)
+		}
+	case nil:
+		return nil, errors.New("cannot instantiate: not a type")
+	default:
+		return nil, fmt.Errorf("cannot instantiate %v: not a generic type", typ)
+	}
+
+	args := make([]types.Type, len(targs))
+	for i, targ := range targs {
+		args[i] = typeOfValue(targ)
+		if args[i] == nil {
+			return nil, fmt.Errorf("cannot instantiate %v: type argument %d is not a type", orig, i)
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	inst, err := types.Instantiate(u.ctxt, orig, args, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot instantiate %v: %w", orig, err)
+	}
+
+	return u.newType(inst), nil
+}
+
+// TypeSet is a type set of a constraint interface.
+type TypeSet struct {
+	// Terms are the normalized terms of the type set such as ~int and string.
+	Terms []*Term
+	// All reports whether the type set is not restricted by terms such as any and fmt.Stringer.
+	All bool
+	// Methods are the methods which all types in the type set have.
+	Methods []*Func
+	// Comparable reports whether all types in the type set are comparable.
+	Comparable bool
+}
+
+var _ fmt.Stringer = (*TypeSet)(nil)
+
+// NewTypeSet creates a [TypeSet] of the constraint in a new [Universe].
+func NewTypeSet(constraint any) *TypeSet {
+	return NewUniverse().TypeSet(constraint)
+}
+
+// TypeSet returns a [TypeSet] of the constraint in the universe.
+// The constraint is an interface type, a type name of an interface or a [TypeParam].
+// It returns nil if the constraint is not an interface.
+func (u *Universe) TypeSet(constraint any) *TypeSet {
+	iface := interfaceOfValue(constraint)
+	if iface == nil {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	terms, all := typeSetTerms(iface)
+	ts := &TypeSet{
+		Terms:      make([]*Term, len(terms)),
+		All:        all,
+		Methods:    make([]*Func, iface.NumMethods()),
+		Comparable: iface.IsComparable(),
+	}
+
+	for i, t := range terms {
+		ts.Terms[i] = u.newTerm(t)
+	}
+
+	for i := range ts.Methods {
+		ts.Methods[i] = u.newFunc(iface.Method(i))
+	}
+
+	return ts
+}
+
+// String returns the terms of the type set such as "~int | string".
+// It returns "any" if the type set is not restricted by terms and
+// "empty" if the type set has no types.
+func (ts *TypeSet) String() string {
+	switch {
+	case ts.All:
+		return "any"
+	case len(ts.Terms) == 0:
+		return "empty"
+	}
+
+	terms := make([]string, len(ts.Terms))
+	for i, t := range ts.Terms {
+		terms[i] = t.String()
+	}
+	return strings.Join(terms, " | ")
+}
+
+// satisfies reports whether the type satisfies the constraint.
+// The constraint is an interface type, a type name of an interface or a [TypeParam].
+func satisfies(t, constraint any) bool {
+	typ, iface := unalias(t), interfaceOfValue(constraint)
+	if typ == nil || iface == nil {
+		return false
+	}
+	return types.Satisfies(typ, iface)
+}
+
+// typeSetTerms returns terms of the type set of the constraint.
+// all is true if the type set is not restricted by terms such as any and fmt.Stringer.
+// The terms are normalized by intersection of embedded elements.
+func typeSetTerms(iface *types.Interface) (terms []*types.Term, all bool) {
+	all = true
+	for i := range iface.NumEmbeddeds() {
+		var (
+			ets  []*types.Term
+			eall bool
+		)
+
+		switch e := iface.EmbeddedType(i).(type) {
+		case *types.Union:
+			for j := range e.Len() {
+				t := e.Term(j)
+				if it, ok := t.Type().Underlying().(*types.Interface); ok {
+					// an interface term without methods such as interface{ int | string } | bool
+					its, iall := typeSetTerms(it)
+					if iall {
+						eall = true
+					}
+					ets = append(ets, its...)
+					continue
+				}
+				ets = append(ets, t)
+			}
+		default:
+			if it, ok := e.Underlying().(*types.Interface); ok {
+				ets, eall = typeSetTerms(it)
+			} else {
+				ets = []*types.Term{types.NewTerm(false, e)}
+			}
+		}
+
+		switch {
+		case eall:
+			continue
+		case all:
+			terms, all = ets, false
+		default:
+			terms = intersectTerms(terms, ets)
+		}
+	}
+
+	return uniqueTerms(terms), all
+}
+
+func intersectTerms(xs, ys []*types.Term) []*types.Term {
+	var terms []*types.Term
+	for _, x := range xs {
+		for _, y := range ys {
+			if t := intersectTerm(x, y); t != nil {
+				terms = append(terms, t)
+			}
+		}
+	}
+	return terms
+}
+
+// intersectTerm returns an intersection of the terms or nil if it is empty.
+func intersectTerm(x, y *types.Term) *types.Term {
+	switch {
+	case x.Tilde() && y.Tilde():
+		if types.Identical(x.Type().Underlying(), y.Type().Underlying()) {
+			return x
+		}
+	case x.Tilde():
+		if types.Identical(x.Type().Underlying(), y.Type().Underlying()) {
+			return y
+		}
+	case y.Tilde():
+		if types.Identical(x.Type().Underlying(), y.Type().Underlying()) {
+			return x
+		}
+	default:
+		if types.Identical(x.Type(), y.Type()) {
+			return x
+		}
+	}
+	return nil
+}
+
+func uniqueTerms(terms []*types.Term) []*types.Term {
+	var unique []*types.Term
+	for _, t := range terms {
+		var found bool
+		for _, ut := range unique {
+			if ut.Tilde() == t.Tilde() && types.Identical(ut.Type(), t.Type()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// coreType returns the core type of t.
+// The core type of a type parameter is the single underlying type of its type set.
+// It returns nil if t does not have a core type.
+func coreType(t types.Type) types.Type {
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return t.Underlying()
+	}
+
+	terms, all := typeSetTerms(iface)
+	if all || len(terms) == 0 {
+		return nil
+	}
+
+	core := terms[0].Type().Underlying()
+	for _, term := range terms[1:] {
+		if !types.Identical(core, term.Type().Underlying()) {
+			return nil
+		}
+	}
+	return core
+}
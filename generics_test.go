@@ -0,0 +1,246 @@
+package knife
+
+import (
+	"bytes"
+	"go/types"
+	"testing"
+)
+
+func TestGenerics(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/generics/a")
+	pkg := k.KnifePackages()[0]
+
+	list := pkg.Types()["List"]
+	if !list.Generic || len(list.TypeParams) != 1 || list.TypeParams[0].Name != "T" {
+		t.Errorf("List must have a type parameter T: %v", list.TypeParams)
+	}
+
+	named := list.Type.Named()
+	if !named.Generic || named.Origin != named || len(named.TypeArgs) != 0 {
+		t.Errorf("List must be a generic type: %v", named)
+	}
+
+	if got, want := named.TypeParams[0].Constraint.String(), "any"; got != want {
+		t.Errorf("constraint: got %q, want %q", got, want)
+	}
+
+	intList := pkg.Vars()["IntList"].Type.Named()
+	if intList.Generic || len(intList.TypeArgs) != 1 || intList.TypeArgs[0].String() != "int" {
+		t.Errorf("List[int] must be an instantiated type: %v", intList.TypeArgs)
+	}
+
+	if intList.Origin != named {
+		t.Errorf("origin: got %v, want %v", intList.Origin, named)
+	}
+
+	if pair := pkg.Types()["Pair"]; len(pair.TypeParams) != 2 || pair.TypeParams[1].Index != 1 {
+		t.Errorf("Pair must have two type parameters: %v", pair.TypeParams)
+	}
+
+	if number := pkg.Types()["Number"]; number.Generic {
+		t.Error("Number must not be generic")
+	}
+
+	mapFunc := pkg.Funcs()["Map"]
+	if sig := mapFunc.Signature; !sig.Generic || len(sig.TypeParams) != 2 || sig.TypeParams[1].Name != "U" {
+		t.Errorf("Map must have type parameters T and U: %v", sig.TypeParams)
+	}
+
+	if got, want := mapFunc.String(), "func github.com/gostaticanalysis/knife/testdata/generics/a.Map[T, U any](xs []T, f func(T) U) []U"; got != want {
+		t.Errorf("string: got %q, want %q", got, want)
+	}
+
+	push := named.Methods()["Push"].Signature
+	if !push.Generic || len(push.TypeParams) != 0 || len(push.RecvTypeParams) != 1 {
+		t.Errorf("Push must have a receiver type parameter: %v", push.RecvTypeParams)
+	}
+
+	cases := []struct {
+		fn   string
+		core string
+	}{
+		{fn: "Map", core: ""},
+		{fn: "Sum", core: ""},
+		{fn: "Bytes", core: "[]byte"},
+	}
+
+	for _, tt := range cases {
+		tp := pkg.Funcs()[tt.fn].Signature.TypeParams[0]
+		var got string
+		if tp.Core != nil {
+			got = tp.Core.String()
+		}
+
+		if got != tt.core {
+			t.Errorf("core type of %s: got %q, want %q", tt.fn, got, tt.core)
+		}
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/generics/a")
+	pkg := k.KnifePackages()[0]
+	u := NewUniverse()
+
+	cache := pkg.Types()["Cache"]
+	inst, err := u.Instantiate(cache, types.Typ[types.String], types.Typ[types.Int])
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	named := inst.Named()
+	if named.Generic || len(named.TypeArgs) != 2 || named.Origin.String() != cache.Type.String() {
+		t.Errorf("Cache[string, int] must be an instance of Cache: %v", named.TypeArgs)
+	}
+
+	if got, want := inst.Struct().Fields()["Entries"].Type.String(), "map[string]int"; got != want {
+		t.Errorf("field: got %q, want %q", got, want)
+	}
+
+	if got, want := named.Methods()["Get"].Signature.String(), "func(key string) (int, bool)"; got != want {
+		t.Errorf("method: got %q, want %q", got, want)
+	}
+
+	again, err := u.Instantiate(cache, types.Typ[types.String], types.Typ[types.Int])
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if again != inst {
+		t.Error("same instances must be same objects in a universe")
+	}
+
+	errCases := []struct {
+		name  string
+		t     any
+		targs []any
+	}{
+		{name: "not generic", t: pkg.Types()["Number"], targs: []any{types.Typ[types.Int]}},
+		{name: "instantiated", t: pkg.Vars()["IntList"].Type, targs: []any{types.Typ[types.Int]}},
+		{name: "wrong number", t: cache, targs: []any{types.Typ[types.Int]}},
+		{name: "not satisfied", t: pkg.Funcs()["Sum"], targs: []any{types.Typ[types.String]}},
+		{name: "not type", t: nil, targs: nil},
+	}
+
+	for _, tt := range errCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := u.Instantiate(tt.t, tt.targs...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTypeSet(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/generics/a")
+	pkg := k.KnifePackages()[0]
+
+	cases := []struct {
+		name       string
+		constraint any
+		want       string
+		comparable bool
+		methods    int
+	}{
+		{name: "union", constraint: pkg.Types()["Number"], want: "~int | ~int64 | ~float64", comparable: true},
+		{name: "intersection", constraint: pkg.Types()["Integer"], want: "~int | ~int64", comparable: true},
+		{name: "any", constraint: types.Universe.Lookup("any").Type(), want: "any"},
+		{name: "comparable", constraint: types.Universe.Lookup("comparable").Type(), want: "any", comparable: true},
+		{name: "methods", constraint: types.Universe.Lookup("error").Type(), want: "any", methods: 1},
+		{name: "type parameter", constraint: pkg.Funcs()["Bytes"].Signature.TypeParams[0], want: "~[]byte", comparable: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTypeSet(tt.constraint)
+			if ts == nil {
+				t.Fatal("type set must not be nil")
+			}
+
+			if got := ts.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			if ts.Comparable != tt.comparable {
+				t.Errorf("comparable: got %v, want %v", ts.Comparable, tt.comparable)
+			}
+
+			if len(ts.Methods) != tt.methods {
+				t.Errorf("methods: got %d, want %d", len(ts.Methods), tt.methods)
+			}
+		})
+	}
+
+	if ts := NewTypeSet(types.Typ[types.Int]); ts != nil {
+		t.Errorf("int is not a constraint: %v", ts)
+	}
+}
+
+func TestTemplate_Generics(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/generics/a")
+
+	cases := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "typeparam",
+			tmpl: `{{with index .Funcs "Sum"}}{{$tp := typeparam (index .Signature.Params 0).Type.Slice.Elem}}{{$tp.Name}} {{$tp.Constraint}}{{end}}`,
+			want: "T github.com/gostaticanalysis/knife/testdata/generics/a.Number",
+		},
+		{
+			name: "union",
+			tmpl: `{{range (union .Types.Number).Terms}}{{if .Tilde}}~{{end}}{{.Type}} {{end}}`,
+			want: "~int ~int64 ~float64 ",
+		},
+		{
+			name: "union of type parameter",
+			tmpl: `{{with index .Funcs "Bytes"}}{{union (index .Signature.TypeParams 0)}}{{end}}`,
+			want: "~[]byte",
+		},
+		{
+			name: "instantiate",
+			tmpl: `{{with instantiate .Types.Cache (typeof "string") (typeof "int")}}{{.}} {{(index .Named.Methods "Get").Signature}}{{end}}`,
+			want: "github.com/gostaticanalysis/knife/testdata/generics/a.Cache[string, int] func(key string) (int, bool)",
+		},
+		{
+			name: "instantiate function",
+			tmpl: `{{instantiate .Funcs.Map (typeof "int") (typeof "string")}}`,
+			want: "func(xs []int, f func(int) string) []string",
+		},
+		{
+			name: "typeset",
+			tmpl: `{{range (typeset .Types.Integer).Terms}}{{.}} {{end}}`,
+			want: "~int ~int64 ",
+		},
+		{
+			name: "satisfies",
+			tmpl: `{{satisfies (typeof "int") .Types.Number}} {{satisfies (typeof "string") .Types.Number}} {{satisfies (typeof "uint") .Types.Integer}}`,
+			want: "true false false",
+		},
+		{
+			name: "satisfies type parameter",
+			tmpl: `{{with index .Funcs "Sum"}}{{$tp := index .Signature.TypeParams 0}}{{satisfies (typeof "int") $tp}} {{satisfies (typeof "string") $tp}}{{end}}`,
+			want: "true false",
+		},
+		{
+			name: "not typeparam",
+			tmpl: `{{if typeparam .Types.List.Type}}NG{{else}}OK{{end}}`,
+			want: "OK",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := k.Execute(&buf, k.Packages()[0], tt.tmpl, &ExecuteOption{}); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package knife
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GraphFormat is an output format of a graph.
+type GraphFormat int
+
+const (
+	// GraphDOT is the DOT language of Graphviz.
+	GraphDOT GraphFormat = iota
+	// GraphMermaid is a Mermaid flowchart.
+	GraphMermaid
+	// GraphJSON is a JSON object which has nodes and edges.
+	GraphJSON
+)
+
+var _ flag.Value = (*GraphFormat)(nil)
+
+func (f *GraphFormat) String() string {
+	if f == nil {
+		return ""
+	}
+
+	switch *f {
+	case GraphDOT:
+		return "dot"
+	case GraphMermaid:
+		return "mermaid"
+	case GraphJSON:
+		return "json"
+	}
+	return fmt.Sprintf("GraphFormat(%d)", int(*f))
+}
+
+// Set implements [flag.Value].
+func (f *GraphFormat) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "dot":
+		*f = GraphDOT
+	case "mermaid":
+		*f = GraphMermaid
+	case "json":
+		*f = GraphJSON
+	default:
+		return fmt.Errorf("unknown graph format %q: expected dot, mermaid or json", s)
+	}
+	return nil
+}
+
+// ImportGraphOption is an option for [NewImportGraph].
+type ImportGraphOption struct {
+	// ModuleOnly excludes packages which are not in the modules of the root packages.
+	ModuleOnly bool
+	// Depth limits the number of imports from the root packages.
+	// Zero means no limit.
+	Depth int
+	// Collapse is a list of package path prefixes.
+	// Packages which have a prefix are collapsed into a node of the prefix.
+	Collapse []string
+	// Cycles highlights edges which are in import cycles after collapsing.
+	Cycles bool
+	// Highlight highlights edges whose "from -> to" matches the regexp.
+	Highlight *regexp.Regexp
+}
+
+// Set sets an option which is given as "module", "depth=N", "collapse=prefix",
+// "cycles" or "highlight=regexp".
+func (opt *ImportGraphOption) Set(s string) error {
+	key, value, hasValue := strings.Cut(s, "=")
+	switch key {
+	case "module":
+		opt.ModuleOnly = true
+	case "cycles":
+		opt.Cycles = true
+	case "depth":
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			return fmt.Errorf("invalid depth %q: expected a non-negative integer", value)
+		}
+		opt.Depth = depth
+	case "collapse":
+		if value == "" {
+			return fmt.Errorf("collapse requires a package path prefix")
+		}
+		opt.Collapse = append(opt.Collapse, value)
+	case "highlight":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return fmt.Errorf("invalid highlight: %w", err)
+		}
+		opt.Highlight = re
+	default:
+		return fmt.Errorf("unknown graph option %q", s)
+	}
+
+	if hasValue && (key == "module" || key == "cycles") {
+		return fmt.Errorf("graph option %q does not take a value", key)
+	}
+
+	return nil
+}
+
+// ImportGraph is an import graph of packages.
+// Nodes and edges are sorted by package paths and have no duplicates.
+type ImportGraph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is a node of an [ImportGraph].
+type GraphNode struct {
+	// ID is a package path or a collapsed prefix.
+	ID string `json:"id"`
+	// Root reports whether the node has a root package.
+	Root bool `json:"root,omitempty"`
+	// Collapsed reports whether the node is collapsed from packages.
+	Collapsed bool `json:"collapsed,omitempty"`
+}
+
+// GraphEdge is an import edge of an [ImportGraph].
+type GraphEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Cycle     bool   `json:"cycle,omitempty"`
+	Highlight bool   `json:"highlight,omitempty"`
+}
+
+// NewImportGraph creates an import graph from the root packages.
+func NewImportGraph(roots []*Package, opt *ImportGraphOption) *ImportGraph {
+	if opt == nil {
+		opt = &ImportGraphOption{}
+	}
+
+	var modules []string
+	for _, pkg := range roots {
+		if pkg.Module != "" {
+			modules = append(modules, pkg.Module)
+		}
+	}
+
+	inModule := func(path string) bool {
+		return slices.ContainsFunc(modules, func(m string) bool {
+			return hasPathPrefix(path, m)
+		})
+	}
+
+	g := &ImportGraph{Nodes: []*GraphNode{}, Edges: []*GraphEdge{}}
+	nodes := make(map[string]*GraphNode)
+	addNode := func(pkg *Package) string {
+		id, collapsed := opt.collapse(pkg.Path)
+		if nodes[id] == nil {
+			nodes[id] = &GraphNode{ID: id, Collapsed: collapsed}
+			g.Nodes = append(g.Nodes, nodes[id])
+		}
+		return id
+	}
+
+	edges := make(map[GraphEdge]bool)
+	depths := make(map[*Package]int)
+	var queue []*Package
+	for _, pkg := range roots {
+		if _, ok := depths[pkg]; !ok {
+			depths[pkg] = 0
+			queue = append(queue, pkg)
+		}
+		nodes[addNode(pkg)].Root = true
+	}
+
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		depth := depths[pkg]
+		if opt.Depth > 0 && depth >= opt.Depth {
+			continue
+		}
+
+		from := addNode(pkg)
+		for _, imp := range pkg.Imports() {
+			if opt.ModuleOnly && !inModule(imp.Path) {
+				continue
+			}
+
+			to := addNode(imp)
+			if e := (GraphEdge{From: from, To: to}); from != to && !edges[e] {
+				edges[e] = true
+				g.Edges = append(g.Edges, &e)
+			}
+
+			if _, ok := depths[imp]; !ok {
+				depths[imp] = depth + 1
+				queue = append(queue, imp)
+			}
+		}
+	}
+
+	slices.SortFunc(g.Nodes, func(a, b *GraphNode) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	slices.SortFunc(g.Edges, func(a, b *GraphEdge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+
+	if opt.Cycles {
+		g.markCycles()
+	}
+
+	if opt.Highlight != nil {
+		for _, e := range g.Edges {
+			e.Highlight = opt.Highlight.MatchString(e.From + " -> " + e.To)
+		}
+	}
+
+	return g
+}
+
+// collapse returns a node ID of the package path.
+func (opt *ImportGraphOption) collapse(path string) (string, bool) {
+	for _, prefix := range opt.Collapse {
+		if strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, prefix) {
+			return strings.TrimSuffix(prefix, "/"), true
+		}
+
+		if hasPathPrefix(path, prefix) {
+			return prefix, true
+		}
+	}
+	return path, false
+}
+
+// hasPathPrefix reports whether path is prefix or a package under prefix.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// markCycles marks edges whose nodes are in a same strongly connected component.
+func (g *ImportGraph) markCycles() {
+	succs := make(map[string][]string)
+	for _, e := range g.Edges {
+		succs[e.From] = append(succs[e.From], e.To)
+	}
+
+	// Tarjan's algorithm
+	var (
+		index   = make(map[string]int)
+		lowlink = make(map[string]int)
+		onStack = make(map[string]bool)
+		comp    = make(map[string]int)
+		stack   []string
+		ncomp   int
+	)
+
+	var visit func(v string)
+	visit = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range succs[v] {
+			if _, ok := index[w]; !ok {
+				visit(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp[w] = ncomp
+				if w == v {
+					break
+				}
+			}
+			ncomp++
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, ok := index[n.ID]; !ok {
+			visit(n.ID)
+		}
+	}
+
+	for _, e := range g.Edges {
+		e.Cycle = comp[e.From] == comp[e.To]
+	}
+}
+
+// Write writes the graph in the format.
+func (g *ImportGraph) Write(w io.Writer, format GraphFormat) error {
+	switch format {
+	case GraphDOT:
+		return g.WriteDOT(w)
+	case GraphMermaid:
+		return g.WriteMermaid(w)
+	case GraphJSON:
+		return g.WriteJSON(w)
+	}
+	return fmt.Errorf("unsupported graph format: %s", &format)
+}
+
+// WriteDOT writes the graph in the DOT language.
+// Root packages are bold, edges in cycles are red
+// and highlighted edges are thick.
+func (g *ImportGraph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph imports {\n")
+	for _, n := range g.Nodes {
+		var attrs []string
+		if n.Root {
+			attrs = append(attrs, "style=bold")
+		}
+		if n.Collapsed {
+			attrs = append(attrs, "shape=box")
+		}
+		fmt.Fprintf(&b, "\t%s%s;\n", strconv.Quote(n.ID), dotAttrs(attrs))
+	}
+
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Cycle {
+			attrs = append(attrs, "color=red")
+		}
+		if e.Highlight {
+			attrs = append(attrs, "penwidth=3")
+		}
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", strconv.Quote(e.From), strconv.Quote(e.To), dotAttrs(attrs))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotAttrs(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+// Root packages are bold, edges in cycles are red
+// and highlighted edges are thick.
+func (g *ImportGraph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = "n" + strconv.Itoa(i)
+		shape := `["%s"]`
+		if n.Collapsed {
+			shape = `[["%s"]]`
+		}
+		fmt.Fprintf(&b, "\t%s"+shape+"\n", ids[n.ID], strings.ReplaceAll(n.ID, `"`, "#quot;"))
+	}
+
+	var styles []string
+	for i, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s --> %s\n", ids[e.From], ids[e.To])
+
+		var style []string
+		if e.Cycle {
+			style = append(style, "stroke:red")
+		}
+		if e.Highlight {
+			style = append(style, "stroke-width:3px")
+		}
+		if len(style) > 0 {
+			styles = append(styles, fmt.Sprintf("\tlinkStyle %d %s\n", i, strings.Join(style, ",")))
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if n.Root {
+			fmt.Fprintf(&b, "\tstyle %s font-weight:bold\n", ids[n.ID])
+		}
+	}
+
+	for _, s := range styles {
+		b.WriteString(s)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the graph as a JSON object.
+func (g *ImportGraph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
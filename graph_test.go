@@ -0,0 +1,198 @@
+package knife
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewImportGraph(t *testing.T) {
+	const prefix = "github.com/gostaticanalysis/knife/testdata/graph/"
+
+	cases := []struct {
+		name    string
+		pattern string
+		opt     *ImportGraphOption
+		nodes   []string
+		edges   []string
+	}{
+		{
+			name:    "module",
+			pattern: "./testdata/graph/...",
+			opt:     &ImportGraphOption{ModuleOnly: true},
+			nodes:   []string{"a/x*", "a/y*", "b*"},
+			edges:   []string{"a/x -> b", "b -> a/y"},
+		},
+		{
+			name:    "all",
+			pattern: "./testdata/graph/a/y",
+			opt:     &ImportGraphOption{Depth: 1},
+			nodes:   []string{"a/y*", "strings"},
+			edges:   []string{"a/y -> strings"},
+		},
+		{
+			name:    "depth",
+			pattern: "./testdata/graph/a/x",
+			opt:     &ImportGraphOption{Depth: 1},
+			nodes:   []string{"a/x*", "b"},
+			edges:   []string{"a/x -> b"},
+		},
+		{
+			name:    "collapse",
+			pattern: "./testdata/graph/...",
+			opt: &ImportGraphOption{
+				ModuleOnly: true,
+				Collapse:   []string{prefix + "a"},
+				Cycles:     true,
+			},
+			nodes: []string{"a*", "b*"},
+			edges: []string{"a -> b (cycle)", "b -> a (cycle)"},
+		},
+		{
+			name:    "highlight",
+			pattern: "./testdata/graph/...",
+			opt: &ImportGraphOption{
+				ModuleOnly: true,
+				Cycles:     true,
+				Highlight:  regexp.MustCompile(`^\S+/b -> `),
+			},
+			nodes: []string{"a/x*", "a/y*", "b*"},
+			edges: []string{"a/x -> b", "b -> a/y (highlight)"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newTestKnife(t, &KnifeOption{Tests: true}, tt.pattern)
+
+			g := NewImportGraph(k.KnifePackages(), tt.opt)
+
+			var nodes []string
+			for _, n := range g.Nodes {
+				id := strings.TrimPrefix(n.ID, prefix)
+				if n.Root {
+					id += "*"
+				}
+				nodes = append(nodes, id)
+			}
+
+			if !slices.Equal(nodes, tt.nodes) {
+				t.Errorf("nodes: got %q, want %q", nodes, tt.nodes)
+			}
+
+			var edges []string
+			for _, e := range g.Edges {
+				edge := strings.TrimPrefix(e.From, prefix) + " -> " + strings.TrimPrefix(e.To, prefix)
+				if e.Cycle {
+					edge += " (cycle)"
+				}
+				if e.Highlight {
+					edge += " (highlight)"
+				}
+				edges = append(edges, edge)
+			}
+
+			if !slices.Equal(edges, tt.edges) {
+				t.Errorf("edges: got %q, want %q", edges, tt.edges)
+			}
+		})
+	}
+}
+
+func TestImportGraph_Write(t *testing.T) {
+	g := &ImportGraph{
+		Nodes: []*GraphNode{{ID: "a", Root: true}, {ID: "b", Collapsed: true}},
+		Edges: []*GraphEdge{{From: "a", To: "b", Cycle: true}, {From: "b", To: "a", Cycle: true, Highlight: true}},
+	}
+
+	cases := []struct {
+		format GraphFormat
+		want   string
+	}{
+		{
+			format: GraphDOT,
+			want: `digraph imports {
+	"a" [style=bold];
+	"b" [shape=box];
+	"a" -> "b" [color=red];
+	"b" -> "a" [color=red, penwidth=3];
+}
+`,
+		},
+		{
+			format: GraphMermaid,
+			want: `graph LR
+	n0["a"]
+	n1[["b"]]
+	n0 --> n1
+	n1 --> n0
+	style n0 font-weight:bold
+	linkStyle 0 stroke:red
+	linkStyle 1 stroke:red,stroke-width:3px
+`,
+		},
+		{
+			format: GraphJSON,
+			want: `{
+  "nodes": [
+    {
+      "id": "a",
+      "root": true
+    },
+    {
+      "id": "b",
+      "collapsed": true
+    }
+  ],
+  "edges": [
+    {
+      "from": "a",
+      "to": "b",
+      "cycle": true
+    },
+    {
+      "from": "b",
+      "to": "a",
+      "cycle": true,
+      "highlight": true
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			var buf strings.Builder
+			if err := g.Write(&buf, tt.format); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Graph(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{Tests: true}, "./testdata/graph/a/x")
+
+	var buf strings.Builder
+	tmpl := `{{graph "mermaid" . "depth=1" "collapse=github.com/gostaticanalysis/knife/testdata/graph/"}}`
+	if err := k.Execute(&buf, k.Packages()[0], tmpl, &ExecuteOption{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	want := "graph LR\n\tn0[[\"github.com/gostaticanalysis/knife/testdata/graph\"]]\n\tstyle n0 font-weight:bold\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	err := k.Execute(&buf, k.Packages()[0], `{{graph "svg" .}}`, &ExecuteOption{})
+	if err == nil {
+		t.Error("expected error but got nil")
+	}
+}
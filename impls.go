@@ -0,0 +1,265 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"go/types"
+	"slices"
+)
+
+// Implementation is a pair of a concrete type and an interface
+// which is implemented by the type.
+type Implementation struct {
+	// Type is the concrete type.
+	Type *TypeName
+	// Interface is the implemented interface.
+	Interface *Type
+	// Pointer reports whether only *T implements the interface.
+	Pointer bool
+}
+
+var _ fmt.Stringer = (*Implementation)(nil)
+
+// Receiver returns "*T" if only *T implements the interface, otherwise "T".
+func (impl *Implementation) Receiver() string {
+	if impl.Pointer {
+		return "*T"
+	}
+	return "T"
+}
+
+// TypeString returns the concrete type in the receiver form such as "*io.PipeReader".
+func (impl *Implementation) TypeString() string {
+	typ := impl.Type.Type.TypesType
+	if impl.Pointer {
+		typ = types.NewPointer(typ)
+	}
+	return typ.String()
+}
+
+func (impl *Implementation) String() string {
+	return impl.TypeString() + " implements " + impl.Interface.String()
+}
+
+// Implementers returns concrete types in pkgs whose T or *T implements iface.
+// iface is an interface type or an object which has an interface type.
+// If deps is true, dependencies of pkgs are also searched.
+// Generic types are not reported.
+func Implementers(pkgs []*Package, iface any, deps bool) []*Implementation {
+	it := interfaceOfValue(iface)
+	if it == nil || len(pkgs) == 0 {
+		return nil
+	}
+
+	ifaceType := typeOfValue(iface)
+	if ifaceType == nil {
+		ifaceType = it
+	}
+	u := pkgs[0].u
+
+	var impls []*Implementation
+	for _, tn := range typeNames(pkgs, deps) {
+		if isInterface(tn) || isGeneric(tn) {
+			continue
+		}
+
+		if impl := implementation(tn, it); impl != nil {
+			impl.Interface = u.Type(ifaceType)
+			impls = append(impls, impl)
+		}
+	}
+
+	return impls
+}
+
+// InterfacesOf returns named interfaces in pkgs which are implemented by T or *T of typ.
+// If deps is true, dependencies of pkgs are also searched.
+// Empty interfaces, constraint interfaces and generic interfaces are not reported.
+func InterfacesOf(pkgs []*Package, typ any, deps bool) []*Implementation {
+	t := typeOfValue(typ)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	if t == nil || types.IsInterface(t) {
+		return nil
+	}
+
+	tn := typeNameOf(pkgs, t)
+	if tn == nil {
+		return nil
+	}
+
+	var impls []*Implementation
+	for _, itn := range typeNames(pkgs, deps) {
+		if !isInterface(itn) || isGeneric(itn) {
+			continue
+		}
+
+		it := itn.Type.TypesType.Underlying().(*types.Interface)
+		if it.Empty() || !it.IsMethodSet() {
+			continue
+		}
+
+		if impl := implementation(tn, it); impl != nil {
+			impl.Interface = itn.Type
+			impls = append(impls, impl)
+		}
+	}
+
+	return impls
+}
+
+// MissingMethod is a method of an interface which is not in the method set of a type.
+type MissingMethod struct {
+	// Method is the method of the interface.
+	Method *Func
+	// Actual is the method of the type which has the same name.
+	// It is nil if the type does not have the method.
+	Actual *Func
+	// WrongSignature reports whether Actual has a different signature from Method.
+	WrongSignature bool
+	// Pointer reports whether Actual has a pointer receiver,
+	// so that only *T has the method. It is independent of WrongSignature.
+	Pointer bool
+}
+
+var _ fmt.Stringer = (*MissingMethod)(nil)
+
+func (m *MissingMethod) String() string {
+	switch {
+	case m.Actual == nil:
+		return fmt.Sprintf("missing method %s: want %s", m.Method.Name, m.Method.Signature)
+	case m.WrongSignature:
+		return fmt.Sprintf("wrong signature of %s: have %s, want %s", m.Method.Name, m.Actual.Signature, m.Method.Signature)
+	}
+	return fmt.Sprintf("method %s has a pointer receiver: only *T has the method", m.Method.Name)
+}
+
+// MissingMethods returns methods of the interface which are not in the method set of the type
+// in a new [Universe]. See [Universe.MissingMethods].
+func MissingMethods(typ, iface any) []*MissingMethod {
+	return NewUniverse().MissingMethods(typ, iface)
+}
+
+// MissingMethods returns methods of the interface which are not in the method set of the type.
+// It returns nil if the type implements the interface.
+// If all of the missing methods are marked by Pointer and none of them are marked by WrongSignature,
+// only *T implements the interface.
+func (u *Universe) MissingMethods(typ, iface any) []*MissingMethod {
+	t, it := unalias(typ), interfaceOfValue(iface)
+	if t == nil || it == nil {
+		return nil
+	}
+
+	mset := types.NewMethodSet(t)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var missing []*MissingMethod
+	for i := range it.NumMethods() {
+		m := it.Method(i)
+		sel := mset.Lookup(m.Pkg(), m.Name())
+		if sel != nil && types.Identical(sel.Obj().Type(), m.Type()) {
+			continue
+		}
+
+		mm := &MissingMethod{Method: u.newFunc(m)}
+		// a method of *T is also looked up from an addressable T
+		if obj, _, _ := types.LookupFieldOrMethod(t, true, m.Pkg(), m.Name()); obj != nil {
+			if f, ok := obj.(*types.Func); ok {
+				mm.Actual = u.newFunc(f)
+				mm.WrongSignature = !types.Identical(f.Type(), m.Type())
+				// the method is not in the method set of T because of its pointer receiver
+				mm.Pointer = sel == nil
+			}
+		}
+		missing = append(missing, mm)
+	}
+
+	return missing
+}
+
+func implementation(tn *TypeName, iface *types.Interface) *Implementation {
+	t := tn.Type.TypesType
+	switch {
+	case types.Implements(t, iface):
+		return &Implementation{Type: tn}
+	case types.Implements(types.NewPointer(t), iface):
+		return &Implementation{Type: tn, Pointer: true}
+	}
+	return nil
+}
+
+// typeNames returns non-alias type names of pkgs sorted by their package paths and names.
+func typeNames(pkgs []*Package, deps bool) []*TypeName {
+	if deps {
+		pkgs = withDeps(pkgs)
+	}
+
+	var tns []*TypeName
+	for _, pkg := range pkgs {
+		for _, name := range pkg.TypeNames() {
+			tn := pkg.Types()[name]
+			if !tn.IsAlias {
+				tns = append(tns, tn)
+			}
+		}
+	}
+
+	slices.SortStableFunc(tns, func(a, b *TypeName) int {
+		return cmp.Or(
+			cmp.Compare(a.Package.Path, b.Package.Path),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return slices.CompactFunc(tns, func(a, b *TypeName) bool {
+		return a.TypesTypeName == b.TypesTypeName
+	})
+}
+
+// typeNameOf returns a type name of t in the universe of pkgs.
+func typeNameOf(pkgs []*Package, t types.Type) *TypeName {
+	named, _ := types.Unalias(t).(*types.Named)
+	if named == nil || len(pkgs) == 0 {
+		return nil
+	}
+	return pkgs[0].u.TypeName(named.Obj())
+}
+
+// withDeps returns pkgs and all of their dependencies.
+func withDeps(pkgs []*Package) []*Package {
+	var (
+		all  []*Package
+		seen = make(map[*Package]bool)
+	)
+
+	var visit func(pkg *Package)
+	visit = func(pkg *Package) {
+		if pkg == nil || seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		all = append(all, pkg)
+		for _, imp := range pkg.Imports() {
+			visit(imp)
+		}
+	}
+
+	for _, pkg := range pkgs {
+		visit(pkg)
+	}
+
+	return all
+}
+
+func isInterface(tn *TypeName) bool {
+	return types.IsInterface(tn.Type.TypesType)
+}
+
+func isGeneric(tn *TypeName) bool {
+	named, _ := tn.Type.TypesType.(*types.Named)
+	return named != nil && named.TypeParams().Len() > 0
+}
@@ -0,0 +1,120 @@
+package knife
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"slices"
+	"testing"
+)
+
+func TestImplementers(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/program/...")
+
+	prog := k.Program()
+	reader := prog.Lookup("io.Reader")
+	if reader == nil {
+		t.Fatal("io.Reader is not found")
+	}
+
+	got := implStrings(Implementers(prog.Packages, reader, false))
+	want := []string{
+		"*github.com/gostaticanalysis/knife/testdata/program/a.File *T",
+		"github.com/gostaticanalysis/knife/testdata/program/b.Buffer T",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Implementers: got %q, want %q", got, want)
+	}
+
+	if got := Implementers(prog.Packages, prog.Lookup("fmt.Println"), false); got != nil {
+		t.Errorf("Implementers must return nil for a non-interface: %v", got)
+	}
+
+	file := prog.Types()["github.com/gostaticanalysis/knife/testdata/program/a.File"]
+	ifaces := InterfacesOf(prog.Packages, file, true)
+	i := slices.IndexFunc(ifaces, func(impl *Implementation) bool {
+		return impl.Interface.String() == "io.Reader"
+	})
+	switch {
+	case i == -1:
+		t.Errorf("InterfacesOf: io.Reader is not found in %v", ifaces)
+	case !ifaces[i].Pointer:
+		t.Error("InterfacesOf: only *File implements io.Reader")
+	}
+
+	if got := InterfacesOf(prog.Packages, file, false); len(got) != 0 {
+		t.Errorf("InterfacesOf without dependencies: got %v, want empty", got)
+	}
+}
+
+func implStrings(impls []*Implementation) []string {
+	ss := make([]string, len(impls))
+	for i, impl := range impls {
+		ss[i] = impl.TypeString() + " " + impl.Receiver()
+	}
+	return ss
+}
+
+func TestMissingMethods(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/missing/a")
+	pkg := k.KnifePackages()[0]
+	store := pkg.Types()["Store"]
+
+	cases := []struct {
+		typ  any
+		want []string
+		// pointer is names of methods which are marked by Pointer
+		pointer []string
+	}{
+		{typ: pkg.Types()["Memory"], want: []string{
+			"method Close has a pointer receiver: only *T has the method",
+			"method Get has a pointer receiver: only *T has the method",
+			"method Put has a pointer receiver: only *T has the method",
+		}, pointer: []string{"Close", "Get", "Put"}},
+		{typ: types.NewPointer(pkg.Types()["Memory"].Type.TypesType), want: nil},
+		{typ: pkg.Types()["Cached"], want: nil},
+		{typ: pkg.Types()["File"], want: []string{
+			"missing method Close: want func() error",
+			"wrong signature of Put: have func(key string, value []byte) error, want func(key string, value string) error",
+		}},
+		{typ: pkg.Types()["Disk"], want: []string{
+			"method Close has a pointer receiver: only *T has the method",
+			"method Get has a pointer receiver: only *T has the method",
+			"wrong signature of Put: have func(key string, value []byte) error, want func(key string, value string) error",
+		}, pointer: []string{"Close", "Get", "Put"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprint(tt.typ), func(t *testing.T) {
+			var got, pointer []string
+			for _, m := range MissingMethods(tt.typ, store) {
+				got = append(got, m.String())
+				if m.Pointer {
+					pointer = append(pointer, m.Method.Name)
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			if !slices.Equal(pointer, tt.pointer) {
+				t.Errorf("pointer: got %q, want %q", pointer, tt.pointer)
+			}
+		})
+	}
+}
+
+func TestTemplate_MissingMethods(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/missing/a")
+
+	tmpl := `{{range missingMethods .Types.File .Types.Store}}{{.Method.Name}}:{{if .Actual}}{{.Actual.Signature}}{{end}} {{end}}`
+	var buf bytes.Buffer
+	if err := k.Execute(&buf, k.Packages()[0], tmpl, &ExecuteOption{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got, want := buf.String(), "Close: Put:func(key string, value []byte) error "; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
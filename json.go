@@ -0,0 +1,587 @@
+package knife
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// JSONSchema is a JSON Schema of the output of [MarshalJSON].
+//
+//go:embed knife.schema.json
+var JSONSchema string
+
+// JSONOption is an option for [MarshalJSON].
+type JSONOption struct {
+	// Fset is used to encode positions. If it is nil, positions are omitted.
+	Fset *token.FileSet
+	// Depth limits nesting of entities which have IDs such as packages, objects and named types.
+	// An entity which is deeper than the limit or has already been encoded
+	// is encoded as a reference such as {"ref": "func:io.Copy"}.
+	// Zero means no limit.
+	Depth int
+	// Indent is used to indent the JSON.
+	Indent string
+}
+
+// MarshalJSON encodes v which is a knife model value such as [*Package], [*Program],
+// an [Object], a [*Type] or a slice or a map of them into JSON.
+// The model has cycles such as Var.Package and Package.Vars, so each entity
+// is encoded only once and referred by its stable ID after that.
+// IDs are "package:<path>", "func:<full name>", "var:<path>.<name>",
+// "const:<path>.<name>", "typename:<path>.<name>" and "type:<qualified type>".
+// IDs of a test variant of a package and its members have a suffix such as
+// "package:p [p.test]" if the variant is a [*Package] in v or an import of it.
+// The output follows [JSONSchema].
+func MarshalJSON(v any, opt *JSONOption) ([]byte, error) {
+	if opt == nil {
+		opt = &JSONOption{}
+	}
+
+	e := &jsonEncoder{
+		opt:      opt,
+		seen:     make(map[string]bool),
+		visiting: make(map[any]bool),
+		forTest:  make(map[*types.Package]string),
+	}
+	e.addVariants(v)
+	data := e.value(v)
+	if opt.Indent != "" {
+		return json.MarshalIndent(data, "", opt.Indent)
+	}
+	return json.Marshal(data)
+}
+
+type jsonEncoder struct {
+	opt   *JSONOption
+	seen  map[string]bool
+	depth int
+	// visiting has pointers to structs which are being encoded
+	visiting map[any]bool
+	// forTest maps test variants of packages to the packages which they are for
+	forTest map[*types.Package]string
+}
+
+// addVariants finds test variants in the packages of v and their imports
+// before encoding because they may be referred before they are encoded.
+func (e *jsonEncoder) addVariants(v any) {
+	var pkgs []*Package
+	switch v := v.(type) {
+	case *Program:
+		pkgs = v.Packages
+	case []*Package:
+		pkgs = v
+	case *Package:
+		pkgs = []*Package{v}
+	}
+
+	for _, pkg := range pkgs {
+		for _, p := range append(pkg.Imports(), pkg) {
+			if p.ForTest != "" {
+				e.forTest[p.TypesPackage] = p.ForTest
+			}
+		}
+	}
+}
+
+// variant returns a suffix of IDs such as " [p.test]" if pkg is a test variant.
+func (e *jsonEncoder) variant(pkg *types.Package) string {
+	if forTest, ok := e.forTest[pkg]; ok {
+		return " [" + forTest + ".test]"
+	}
+	return ""
+}
+
+// pkgID returns a stable ID of the package.
+func (e *jsonEncoder) pkgID(pkg *types.Package) string {
+	return "package:" + pkg.Path() + e.variant(pkg)
+}
+
+// jsonObject is an encoded value.
+// encoding/json sorts its keys, so the output is stable.
+type jsonObject map[string]any
+
+// entity encodes a value which has the ID with fill
+// or a reference if it has already been encoded or it is too deep.
+func (e *jsonEncoder) entity(id, kind string, fill func(obj jsonObject)) jsonObject {
+	if e.seen[id] || (e.opt.Depth > 0 && e.depth >= e.opt.Depth) {
+		return jsonObject{"ref": id}
+	}
+	e.seen[id] = true
+
+	e.depth++
+	defer func() { e.depth-- }()
+
+	obj := jsonObject{"id": id, "kind": kind}
+	fill(obj)
+	return obj
+}
+
+func (e *jsonEncoder) value(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *Program:
+		pkgs := make([]any, len(v.Packages))
+		for i, pkg := range v.Packages {
+			pkgs[i] = e.pkg(pkg.TypesPackage, pkg)
+		}
+		return jsonObject{"kind": "program", "packages": pkgs}
+	case *Package:
+		return e.pkg(v.TypesPackage, v)
+	case *types.Package:
+		return e.pkg(v, nil)
+	case *Field:
+		return e.field(v.TypesVar, v.Tag)
+	case Object:
+		return e.object(v.TypesObject())
+	case types.Object:
+		return e.object(v)
+	case *Type:
+		return e.typ(v.TypesType)
+	case types.Type:
+		return e.typ(v)
+	case *ASTNode:
+		return e.other(v)
+	case ast.Node:
+		// an AST may have cycles through ast.Object
+		return e.other(v)
+	}
+
+	if t := wrappedType(v); t != nil {
+		return e.typ(t)
+	}
+
+	return e.reflect(reflect.ValueOf(v))
+}
+
+// reflect encodes slices, maps and structs which contain model values.
+func (e *jsonEncoder) reflect(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface || v.Elem().Kind() != reflect.Struct {
+			return e.value(v.Elem().Interface())
+		}
+
+		p := v.Interface()
+		if e.visiting[p] {
+			return e.other(p)
+		}
+		e.visiting[p] = true
+		defer delete(e.visiting, p)
+		return e.structValue(v.Elem(), p)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []any{}
+		}
+		list := make([]any, v.Len())
+		for i := range v.Len() {
+			list[i] = e.value(v.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		obj := make(jsonObject, len(keys))
+		for _, k := range keys {
+			obj[k.String()] = e.value(v.MapIndex(k).Interface())
+		}
+		return obj
+	case reflect.Struct:
+		return e.structValue(v, v.Interface())
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return v.Interface()
+	}
+
+	return e.other(v.Interface())
+}
+
+// structValue encodes exported fields of a struct and its position.
+func (e *jsonEncoder) structValue(v reflect.Value, orig any) any {
+	if _, ok := orig.(json.Marshaler); ok {
+		return orig
+	}
+
+	obj := jsonObject{}
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if f.IsExported() {
+			obj[lowerFirst(f.Name)] = e.value(v.Field(i).Interface())
+		}
+	}
+
+	if s, ok := orig.(fmt.Stringer); ok {
+		obj["string"] = s.String()
+	}
+	e.setPos(obj, orig)
+
+	return obj
+}
+
+// other encodes a value which is not a model value such as an AST node.
+func (e *jsonEncoder) other(v any) any {
+	obj := jsonObject{"kind": "value", "type": fmt.Sprintf("%T", v), "string": fmt.Sprint(v)}
+	e.setPos(obj, v)
+	return obj
+}
+
+func (e *jsonEncoder) setPos(obj jsonObject, v any) {
+	n, ok := v.(interface{ Pos() token.Pos })
+	if !ok || e.opt.Fset == nil || !n.Pos().IsValid() {
+		return
+	}
+
+	pos := e.opt.Fset.Position(n.Pos())
+	obj["pos"] = jsonObject{"file": pos.Filename, "line": pos.Line, "column": pos.Column}
+}
+
+func (e *jsonEncoder) pkg(pkg *types.Package, kpkg *Package) any {
+	if pkg == nil {
+		return nil
+	}
+
+	return e.entity(e.pkgID(pkg), "package", func(obj jsonObject) {
+		obj["name"] = pkg.Name()
+		obj["path"] = pkg.Path()
+
+		if kpkg != nil {
+			if kpkg.Module != "" {
+				obj["module"] = kpkg.Module
+			}
+			if kpkg.ForTest != "" {
+				obj["forTest"] = kpkg.ForTest
+			}
+			if len(kpkg.Errors) > 0 {
+				errs := make([]string, len(kpkg.Errors))
+				for i, err := range kpkg.Errors {
+					errs[i] = err.Error()
+				}
+				obj["errors"] = errs
+			}
+		}
+
+		imports := make([]any, len(pkg.Imports()))
+		for i, imp := range pkg.Imports() {
+			imports[i] = jsonObject{"ref": e.pkgID(imp)}
+		}
+		obj["imports"] = imports
+
+		// types are encoded first so that they are expanded at their declarations
+		scope := pkg.Scope()
+		for _, kind := range []string{"types", "consts", "vars", "funcs"} {
+			list := []any{}
+			for _, name := range scope.Names() {
+				o := scope.Lookup(name)
+				if jsonPackageMember(o) == kind {
+					list = append(list, e.object(o))
+				}
+			}
+			obj[kind] = list
+		}
+	})
+}
+
+// jsonPackageMember returns a key of the package which has the object.
+func jsonPackageMember(o types.Object) string {
+	switch o.(type) {
+	case *types.TypeName:
+		return "types"
+	case *types.Const:
+		return "consts"
+	case *types.Var:
+		return "vars"
+	case *types.Func:
+		return "funcs"
+	}
+	return ""
+}
+
+// objectID returns a stable ID of the object.
+func (e *jsonEncoder) objectID(o types.Object) string {
+	switch o := o.(type) {
+	case *types.Func:
+		return "func:" + o.Origin().FullName() + e.variant(o.Pkg())
+	case *types.PkgName:
+		return e.pkgID(o.Imported())
+	}
+
+	kind := "var"
+	switch o.(type) {
+	case *types.Const:
+		kind = "const"
+	case *types.TypeName:
+		kind = "typename"
+	}
+
+	if o.Pkg() == nil {
+		return kind + ":" + o.Name()
+	}
+
+	// local objects are distinguished by their positions
+	if o.Parent() != nil && o.Parent() != o.Pkg().Scope() {
+		return fmt.Sprintf("%s:%s.%s@%d%s", kind, o.Pkg().Path(), o.Name(), o.Pos(), e.variant(o.Pkg()))
+	}
+	return kind + ":" + o.Pkg().Path() + "." + o.Name() + e.variant(o.Pkg())
+}
+
+func (e *jsonEncoder) object(o types.Object) any {
+	if o == nil {
+		return nil
+	}
+
+	if v, ok := o.(*types.Var); ok && v.IsField() {
+		return e.field(v, "")
+	}
+
+	var kind string
+	switch o.(type) {
+	case *types.Func:
+		kind = "func"
+	case *types.Var:
+		kind = "var"
+	case *types.Const:
+		kind = "const"
+	case *types.TypeName:
+		kind = "typename"
+	default:
+		return e.other(o)
+	}
+
+	return e.entity(e.objectID(o), kind, func(obj jsonObject) {
+		obj["name"] = o.Name()
+		obj["exported"] = o.Exported()
+		e.setPos(obj, o)
+		if o.Pkg() != nil {
+			obj["package"] = jsonObject{"ref": e.pkgID(o.Pkg())}
+		}
+
+		switch o := o.(type) {
+		case *types.Func:
+			obj["signature"] = e.typ(o.Type())
+		case *types.Const:
+			obj["type"] = e.typ(o.Type())
+			obj["value"] = o.Val().ExactString()
+		case *types.TypeName:
+			obj["alias"] = o.IsAlias()
+			obj["type"] = e.typ(o.Type())
+		default:
+			obj["type"] = e.typ(o.Type())
+		}
+	})
+}
+
+func (e *jsonEncoder) field(v *types.Var, tag string) any {
+	obj := jsonObject{
+		"kind":     "field",
+		"name":     v.Name(),
+		"exported": v.Exported(),
+		"embedded": v.Anonymous(),
+		"type":     e.typ(v.Type()),
+	}
+	if tag != "" {
+		obj["tag"] = tag
+	}
+	e.setPos(obj, v)
+	return obj
+}
+
+func (e *jsonEncoder) vars(tuple *types.Tuple) []any {
+	list := make([]any, tuple.Len())
+	for i := range tuple.Len() {
+		v := tuple.At(i)
+		list[i] = jsonObject{"name": v.Name(), "type": e.typ(v.Type())}
+	}
+	return list
+}
+
+func (e *jsonEncoder) typeParams(list *types.TypeParamList) []any {
+	tps := make([]any, list.Len())
+	for i := range list.Len() {
+		tps[i] = e.typ(list.At(i))
+	}
+	return tps
+}
+
+func (e *jsonEncoder) typeList(list *types.TypeList) []any {
+	ts := make([]any, list.Len())
+	for i := range list.Len() {
+		ts[i] = e.typ(list.At(i))
+	}
+	return ts
+}
+
+func (e *jsonEncoder) typ(t types.Type) any {
+	if t == nil {
+		return nil
+	}
+
+	obj := jsonObject{"string": t.String()}
+	switch t := t.(type) {
+	case *types.Named, *types.Alias:
+		named := t.(interface {
+			types.Type
+			Obj() *types.TypeName
+		})
+		kind := "named"
+		if _, ok := t.(*types.Alias); ok {
+			kind = "alias"
+		}
+		return e.entity("type:"+t.String()+e.variant(named.Obj().Pkg()), kind, func(obj jsonObject) {
+			obj["string"] = t.String()
+			obj["object"] = jsonObject{"ref": e.objectID(named.Obj())}
+			if named.Obj().Pkg() == nil {
+				// error and comparable
+				obj["underlying"] = e.typ(t.Underlying())
+				return
+			}
+
+			switch t := t.(type) {
+			case *types.Named:
+				obj["typeParams"] = e.typeParams(t.TypeParams())
+				if t.TypeArgs().Len() > 0 {
+					obj["typeArgs"] = e.typeList(t.TypeArgs())
+					obj["origin"] = jsonObject{"ref": "type:" + t.Origin().String() + e.variant(t.Obj().Pkg())}
+				}
+				obj["underlying"] = e.typ(t.Underlying())
+				methods := make([]any, t.NumMethods())
+				for i := range t.NumMethods() {
+					methods[i] = e.object(t.Method(i))
+				}
+				obj["methods"] = methods
+			case *types.Alias:
+				obj["rhs"] = e.typ(t.Rhs())
+			}
+		})
+	case *types.Basic:
+		obj["kind"] = "basic"
+		obj["name"] = t.Name()
+	case *types.Pointer:
+		obj["kind"] = "pointer"
+		obj["elem"] = e.typ(t.Elem())
+	case *types.Slice:
+		obj["kind"] = "slice"
+		obj["elem"] = e.typ(t.Elem())
+	case *types.Array:
+		obj["kind"] = "array"
+		obj["len"] = t.Len()
+		obj["elem"] = e.typ(t.Elem())
+	case *types.Map:
+		obj["kind"] = "map"
+		obj["key"] = e.typ(t.Key())
+		obj["elem"] = e.typ(t.Elem())
+	case *types.Chan:
+		obj["kind"] = "chan"
+		obj["dir"] = chanDir(t.Dir())
+		obj["elem"] = e.typ(t.Elem())
+	case *types.Struct:
+		obj["kind"] = "struct"
+		fields := make([]any, t.NumFields())
+		for i := range t.NumFields() {
+			fields[i] = e.field(t.Field(i), t.Tag(i))
+		}
+		obj["fields"] = fields
+	case *types.Interface:
+		obj["kind"] = "interface"
+		methods := make([]any, t.NumMethods())
+		for i := range t.NumMethods() {
+			methods[i] = e.object(t.Method(i))
+		}
+		obj["methods"] = methods
+		embeddeds := make([]any, t.NumEmbeddeds())
+		for i := range t.NumEmbeddeds() {
+			embeddeds[i] = e.typ(t.EmbeddedType(i))
+		}
+		obj["embeddeds"] = embeddeds
+	case *types.Signature:
+		obj["kind"] = "signature"
+		if t.Recv() != nil {
+			obj["recv"] = jsonObject{"name": t.Recv().Name(), "type": e.typ(t.Recv().Type())}
+		}
+		if t.TypeParams().Len() > 0 {
+			obj["typeParams"] = e.typeParams(t.TypeParams())
+		}
+		obj["params"] = e.vars(t.Params())
+		obj["results"] = e.vars(t.Results())
+		obj["variadic"] = t.Variadic()
+	case *types.TypeParam:
+		obj["kind"] = "typeparam"
+		obj["name"] = t.Obj().Name()
+		obj["constraint"] = e.typ(t.Constraint())
+	case *types.Union:
+		obj["kind"] = "union"
+		terms := make([]any, t.Len())
+		for i := range t.Len() {
+			terms[i] = jsonObject{"tilde": t.Term(i).Tilde(), "type": e.typ(t.Term(i).Type())}
+		}
+		obj["terms"] = terms
+	default:
+		obj["kind"] = "type"
+	}
+
+	return obj
+}
+
+func chanDir(dir types.ChanDir) string {
+	switch dir {
+	case types.SendOnly:
+		return "send"
+	case types.RecvOnly:
+		return "recv"
+	}
+	return "both"
+}
+
+// wrappedType returns a type which is wrapped by a knife type such as [*Struct].
+func wrappedType(v any) types.Type {
+	switch v := v.(type) {
+	case *Array:
+		return v.TypesArray
+	case *Slice:
+		return v.TypesSlice
+	case *Struct:
+		return v.TypesStruct
+	case *Map:
+		return v.TypesMap
+	case *Pointer:
+		return v.TypesPointer
+	case *Chan:
+		return v.TypesChan
+	case *Basic:
+		return v.TypesBasic
+	case *Interface:
+		return v.TypesInterface
+	case *Signature:
+		return v.TypesSignature
+	case *Named:
+		return v.TypesNamed
+	case *Alias:
+		return v.TypesAlias
+	case *TypeParam:
+		return v.TypesTypeParam
+	case *Union:
+		return v.TypesUnion
+	}
+	return nil
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,282 @@
+package knife
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	const path = "github.com/gostaticanalysis/knife/testdata/json/a"
+
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/json/a")
+	pkg := k.KnifePackages()[0]
+
+	cases := []struct {
+		name  string
+		v     any
+		depth int
+		// want maps a JSON pointer like path to an expected value
+		want map[string]any
+	}{
+		{
+			name: "package",
+			v:    pkg,
+			want: map[string]any{
+				"id":                                   "package:" + path,
+				"kind":                                 "package",
+				"imports/0/ref":                        "package:io",
+				"types/0/id":                           "typename:" + path + ".Node",
+				"types/0/pos/line":                     6.0,
+				"types/0/type/kind":                    "named",
+				"types/0/type/underlying/fields/0/tag": `json:"value"`,
+				// the cycle of Node is encoded as a reference
+				"types/0/type/underlying/fields/1/type/elem/ref": "type:" + path + ".Node",
+				"types/0/type/methods/0/id":                      "func:(*" + path + ".Node).Len",
+				"consts/0/value":                                 "10",
+				"vars/0/type/kind":                               "pointer",
+				"funcs/0/signature/params/0/type/id":             "type:io.Writer",
+				"funcs/0/package/ref":                            "package:" + path,
+			},
+		},
+		{
+			name:  "depth",
+			v:     pkg,
+			depth: 1,
+			want: map[string]any{
+				"id":            "package:" + path,
+				"funcs/0/ref":   "func:" + path + ".Copy",
+				"types/0/ref":   "typename:" + path + ".Node",
+				"imports/0/ref": "package:io",
+			},
+		},
+		{
+			name: "object",
+			v:    pkg.Funcs()["Copy"],
+			want: map[string]any{
+				"id":                         "func:" + path + ".Copy",
+				"kind":                       "func",
+				"exported":                   true,
+				"signature/params/0/type/id": "type:io.Writer",
+				// error is expanded in io.Writer.Write
+				"signature/results/0/type/ref": "type:error",
+			},
+		},
+		{
+			name: "slice",
+			v:    []*Const{pkg.Consts()["Max"]},
+			want: map[string]any{
+				"0/id":          "const:" + path + ".Max",
+				"0/type/kind":   "basic",
+				"0/type/name":   "untyped int",
+				"0/package/ref": "package:" + path,
+			},
+		},
+		{
+			name: "struct",
+			v:    pkg.Types()["Node"].Type.Underlying().Struct(),
+			want: map[string]any{
+				"kind":                  "struct",
+				"fields/0/name":         "Value",
+				"fields/1/type/kind":    "pointer",
+				"fields/1/type/elem/id": "type:" + path + ".Node",
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := MarshalJSON(tt.v, &JSONOption{Fset: k.Packages()[0].Fset, Depth: tt.depth})
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			var got any
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			for p, want := range tt.want {
+				if v := lookupJSON(got, p); v != want {
+					t.Errorf("%s: got %v, want %v", p, v, want)
+				}
+			}
+		})
+	}
+}
+
+func lookupJSON(v any, path string) any {
+	for _, key := range bytes.Split([]byte(path), []byte("/")) {
+		switch x := v.(type) {
+		case map[string]any:
+			v = x[string(key)]
+		case []any:
+			var i int
+			for _, c := range key {
+				i = i*10 + int(c-'0')
+			}
+			if i >= len(x) {
+				return nil
+			}
+			v = x[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func TestMarshalJSON_Variants(t *testing.T) {
+	const path = "github.com/gostaticanalysis/knife/testdata/variants"
+
+	opt := &KnifeOption{Tests: true, Variants: VariantsAll}
+	k := newTestKnife(t, opt, "./testdata/variants")
+
+	b, err := MarshalJSON(k.Program(), nil)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var got any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// each package is encoded because the IDs of the variants are different
+	ids := make(map[any]bool)
+	pkgs := lookupJSON(got, "packages").([]any)
+	for i := range pkgs {
+		id := lookupJSON(pkgs[i], "id")
+		if id == nil || ids[id] {
+			t.Errorf("package %d is not encoded: %v", i, pkgs[i])
+		}
+		ids[id] = true
+	}
+
+	for _, id := range []string{
+		"package:" + path,
+		"package:" + path + " [" + path + ".test]",
+		"package:" + path + "_test [" + path + ".test]",
+	} {
+		if !ids[id] {
+			t.Errorf("%s is not found in %v", id, ids)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Required []string `json:"required"`
+			Pattern  string   `json:"pattern"`
+			AnyOf    []struct {
+				Required []string `json:"required"`
+			} `json:"anyOf"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(JSONSchema), &schema); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(schema.Defs) == 0 {
+		t.Fatal("schema does not have definitions")
+	}
+
+	// required keys of the definitions which are distinguished by kind
+	required := map[string][]string{
+		"program":  schema.Defs["program"].Required,
+		"package":  schema.Defs["package"].Required,
+		"func":     schema.Defs["object"].Required,
+		"var":      schema.Defs["object"].Required,
+		"const":    schema.Defs["object"].Required,
+		"typename": schema.Defs["object"].Required,
+		"field":    schema.Defs["field"].Required,
+		"value":    schema.Defs["other"].Required,
+	}
+	// a type is a reference or an object which has the kind
+	anyOf := schema.Defs["type"].AnyOf
+	if len(anyOf) != 2 {
+		t.Fatal("unexpected definition of type:", anyOf)
+	}
+	for _, kind := range []string{"named", "alias", "basic", "pointer", "slice", "array", "map", "chan", "struct", "interface", "signature", "typeparam", "union", "type"} {
+		required[kind] = anyOf[1].Required
+	}
+
+	id := regexp.MustCompile(schema.Defs["id"].Pattern)
+	for name, req := range required {
+		if len(req) == 0 {
+			t.Errorf("%s does not have required keys", name)
+		}
+	}
+
+	k := newTestKnife(t, &KnifeOption{Tests: true, Variants: VariantsAll}, "./testdata/json/a", "./testdata/variants")
+
+	b, err := MarshalJSON(k.Program(), &JSONOption{Fset: k.Packages()[0].Fset})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var got any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var check func(path string, v any)
+	check = func(path string, v any) {
+		switch v := v.(type) {
+		case []any:
+			for i := range v {
+				check(fmt.Sprint(path, "/", i), v[i])
+			}
+		case map[string]any:
+			if ref, ok := v["ref"].(string); ok {
+				if len(v) != 1 || !id.MatchString(ref) {
+					t.Errorf("%s: invalid reference %v", path, v)
+				}
+				return
+			}
+
+			if s, ok := v["id"].(string); ok && !id.MatchString(s) {
+				t.Errorf("%s: invalid id %q", path, s)
+			}
+
+			if kind, ok := v["kind"].(string); ok {
+				req, ok := required[kind]
+				if !ok {
+					t.Errorf("%s: unknown kind %q", path, kind)
+				}
+				for _, key := range req {
+					if _, ok := v[key]; !ok {
+						t.Errorf("%s: %s does not have required key %q", path, kind, key)
+					}
+				}
+			}
+
+			for key, elem := range v {
+				check(path+"/"+key, elem)
+			}
+		}
+	}
+	check("", got)
+}
+
+func TestTemplate_JSON(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/json/a")
+
+	var buf bytes.Buffer
+	tmpl := `{{json (index .Vars "Head") 1}}`
+	if err := k.Execute(&buf, k.Packages()[0], tmpl, &ExecuteOption{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got["kind"] != "var" || got["name"] != "Head" {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"go/token"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/gostaticanalysis/astquery"
 	"golang.org/x/tools/go/ast/inspector"
@@ -22,9 +23,15 @@ func Version() string {
 }
 
 type Knife struct {
-	fset *token.FileSet
-	pkgs []*packages.Package
-	ins  map[*packages.Package]*inspector.Inspector
+	fset      *token.FileSet
+	pkgs      []*packages.Package
+	ins       map[*packages.Package]*inspector.Inspector
+	merged    map[*packages.Package]*Package
+	platforms []Platform
+	u         *Universe
+	progOnce  sync.Once
+	prog      *Program
+	cg        *CallGraph
 }
 
 func New(opt *KnifeOption, patterns ...string) (*Knife, error) {
@@ -32,12 +39,10 @@ func New(opt *KnifeOption, patterns ...string) (*Knife, error) {
 		opt = &KnifeOption{Tests: true}
 	}
 
-	mode := packages.NeedFiles | packages.NeedSyntax |
-		packages.NeedTypes | packages.NeedDeps | packages.NeedTypesInfo
-	cfg := &packages.Config{
-		Fset:  token.NewFileSet(),
-		Mode:  mode,
-		Tests: opt.Tests,
+	cfg := opt.PackagesConfig(opt.Load.Mode(), opt.Tests)
+	cfg.Fset = opt.Fset
+	if cfg.Fset == nil {
+		cfg.Fset = token.NewFileSet()
 	}
 
 	pkgs, err := packages.Load(cfg, patterns...)
@@ -45,23 +50,77 @@ func New(opt *KnifeOption, patterns ...string) (*Knife, error) {
 		return nil, fmt.Errorf("load: %w", err)
 	}
 
+	pkgs = FilterVariants(pkgs, opt.Variants)
+
+	if opt.Strict {
+		if err := CheckErrors(pkgs); err != nil {
+			return nil, err
+		}
+	}
+
+	u := opt.Universe
+	if u == nil {
+		u = NewUniverse()
+	}
+
 	ins := make(map[*packages.Package]*inspector.Inspector, len(pkgs))
 	for _, pkg := range pkgs {
 		ins[pkg] = inspector.New(pkg.Syntax)
+		SetPackageInfo(u.Package(pkg.Types), pkg)
+	}
+
+	var cg *CallGraph
+	if opt.CallGraph != CallGraphNone {
+		cg, err = NewCallGraph(u, pkgs, opt.CallGraph)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &Knife{
 		fset: cfg.Fset,
 		pkgs: pkgs,
 		ins:  ins,
+		u:    u,
+		cg:   cg,
 	}, nil
 }
 
+// CallGraph returns the call graph which is built by [KnifeOption.CallGraph].
+// It returns nil if the call graph is not built.
+func (k *Knife) CallGraph() *CallGraph {
+	return k.cg
+}
+
 // Packages returns packages.
 func (k *Knife) Packages() []*packages.Package {
 	return k.pkgs
 }
 
+// KnifePackages returns knife packages.
+func (k *Knife) KnifePackages() []*Package {
+	pkgs := make([]*Package, len(k.pkgs))
+	for i := range k.pkgs {
+		pkgs[i] = k.knifePackage(k.pkgs[i])
+	}
+	return pkgs
+}
+
+// Program returns a [Program] which has all packages.
+func (k *Knife) Program() *Program {
+	k.progOnce.Do(func() {
+		k.prog = NewProgram(k.KnifePackages())
+	})
+	return k.prog
+}
+
+func (k *Knife) knifePackage(pkg *packages.Package) *Package {
+	if merged := k.merged[pkg]; merged != nil {
+		return merged
+	}
+	return k.u.Package(pkg.Types)
+}
+
 // Position returns position of v.
 func (k *Knife) Position(v any) token.Position {
 	n, ok := v.(interface{ Pos() token.Pos })
@@ -74,31 +133,51 @@ func (k *Knife) Position(v any) token.Position {
 // KnifeOption is an option for New.
 type KnifeOption struct {
 	Tests bool
+	// Strict reports load, parse and type errors of the packages
+	// and their dependencies as a [*LoadError].
+	// If it is false, the errors are set to Errors field of [Package].
+	Strict bool
+	// Variants is a policy for test variants of packages.
+	Variants VariantPolicy
+	// Load is a strategy for loading packages.
+	Load LoadStrategy
+	BuildContext
+	// Fset is a file set which is used to load packages.
+	// If it is nil, a new file set is created.
+	Fset *token.FileSet
+	// Universe is used to create knife objects.
+	// If it is nil, a new universe is created and owned by the [Knife].
+	Universe *Universe
+	// CallGraph is an algorithm to build a call graph of the packages.
+	// By default, a call graph is not built.
+	// It is not supported by [NewMatrix].
+	CallGraph CallGraphAlgorithm
 }
 
 // ExecuteOption is an option for Execute.
 type ExecuteOption struct {
 	XPath     string
 	ExtraData map[string]any
+	// Report receives diagnostics which are reported by diag and report in the template.
+	Report func(d *Diagnostic)
+	// Edit receives edits which are proposed by replace, insertBefore and delete in the template.
+	Edit func(e *Edit)
+}
+
+// reportFunc returns a function for [TempalteData.Report].
+func (opt *ExecuteOption) reportFunc() func(token.Pos, *Diagnostic) {
+	if opt == nil || opt.Report == nil {
+		return nil
+	}
+	return func(_ token.Pos, d *Diagnostic) { opt.Report(d) }
 }
 
 // Execute outputs the pkg with the format.
 func (k *Knife) Execute(w io.Writer, pkg *packages.Package, tmpl any, opt *ExecuteOption) error {
 
-	var tmplStr string
-	switch tmpl := tmpl.(type) {
-	case string:
-		tmplStr = tmpl
-	case []byte:
-		tmplStr = string(tmpl)
-	case io.Reader:
-		b, err := io.ReadAll(tmpl)
-		if err != nil {
-			return fmt.Errorf("cannnot read template: %w", err)
-		}
-		tmplStr = string(b)
-	default:
-		return fmt.Errorf("template must be string, []byte or io.Reader: %T", tmpl)
+	tmplStr, err := ReadTemplate(tmpl)
+	if err != nil {
+		return err
 	}
 
 	td := &TempalteData{
@@ -107,6 +186,12 @@ func (k *Knife) Execute(w io.Writer, pkg *packages.Package, tmpl any, opt *Execu
 		TypesInfo: pkg.TypesInfo,
 		Pkg:       pkg.Types,
 		Extra:     opt.ExtraData,
+		Universe:  k.u,
+		Program:   k.Program(),
+		Packages:  k.pkgs,
+		CallGraph: k.cg,
+		Report:    opt.reportFunc(),
+		Edit:      opt.Edit,
 	}
 	t, err := NewTemplate(td).Parse(tmplStr)
 	if err != nil {
@@ -122,7 +207,76 @@ func (k *Knife) Execute(w io.Writer, pkg *packages.Package, tmpl any, opt *Execu
 			return err
 		}
 	default:
-		data = NewPackage(pkg.Types)
+		data = k.knifePackage(pkg)
+	}
+
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("template execute: %w", err)
+	}
+
+	return nil
+}
+
+// ExecuteAll outputs all packages with the format at once.
+// The template is given a [*Program] which has all packages
+// or AST nodes of all packages which are selected by the XPath of opt.
+func (k *Knife) ExecuteAll(w io.Writer, tmpl any, opt *ExecuteOption) error {
+	if opt == nil {
+		opt = &ExecuteOption{}
+	}
+
+	var data any = k.Program()
+	if opt.XPath != "" {
+		var nodes []*ASTNode
+		for _, pkg := range k.pkgs {
+			v, err := k.evalXPath(pkg, opt.XPath)
+			if err != nil {
+				return err
+			}
+			ns, ok := v.([]*ASTNode)
+			if !ok {
+				return fmt.Errorf("XPath must select AST nodes with all packages: %T", v)
+			}
+			nodes = append(nodes, ns...)
+		}
+		data = nodes
+	}
+
+	return k.ExecuteData(w, data, tmpl, opt)
+}
+
+// ExecuteData outputs the data with the format.
+// The template can use same functions as [Knife.ExecuteAll].
+// XPath of opt is ignored.
+func (k *Knife) ExecuteData(w io.Writer, data any, tmpl any, opt *ExecuteOption) error {
+	if opt == nil {
+		opt = &ExecuteOption{}
+	}
+
+	tmplStr, err := ReadTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+
+	var files []*ast.File
+	for _, pkg := range k.pkgs {
+		files = append(files, pkg.Syntax...)
+	}
+
+	td := &TempalteData{
+		Fset:      k.fset,
+		Files:     files,
+		Extra:     opt.ExtraData,
+		Universe:  k.u,
+		Program:   k.Program(),
+		Packages:  k.pkgs,
+		CallGraph: k.cg,
+		Report:    opt.reportFunc(),
+		Edit:      opt.Edit,
+	}
+	t, err := NewTemplate(td).Parse(tmplStr)
+	if err != nil {
+		return fmt.Errorf("template parse: %w", err)
 	}
 
 	if err := t.Execute(w, data); err != nil {
@@ -132,6 +286,43 @@ func (k *Knife) Execute(w io.Writer, pkg *packages.Package, tmpl any, opt *Execu
 	return nil
 }
 
+// Refs returns references to the object in all packages.
+// obj is an [Object] or a [types.Object].
+// See [Universe.Refs].
+func (k *Knife) Refs(obj any) []*Ref {
+	return k.u.Refs(k.pkgs, obj)
+}
+
+// Why returns all shortest import paths from the packages to the package which has the path.
+// See [Universe.Why].
+func (k *Knife) Why(path string) []*ImportPath {
+	return k.u.Why(k.pkgs, path)
+}
+
+// CheckLayers returns imports of the packages which violate the rules.
+// See [Universe.CheckLayers].
+func (k *Knife) CheckLayers(rules *LayerRules) []*LayerViolation {
+	return k.u.CheckLayers(k.pkgs, rules)
+}
+
+// ReadTemplate reads a template which is string, []byte or [io.Reader].
+func ReadTemplate(tmpl any) (string, error) {
+	switch tmpl := tmpl.(type) {
+	case string:
+		return tmpl, nil
+	case []byte:
+		return string(tmpl), nil
+	case io.Reader:
+		b, err := io.ReadAll(tmpl)
+		if err != nil {
+			return "", fmt.Errorf("cannnot read template: %w", err)
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("template must be string, []byte or io.Reader: %T", tmpl)
+	}
+}
+
 func (k *Knife) evalXPath(pkg *packages.Package, xpath string) (any, error) {
 	e := astquery.New(pkg.Fset, pkg.Syntax, k.ins[pkg])
 	v, err := e.Eval(xpath)
@@ -143,7 +334,7 @@ func (k *Knife) evalXPath(pkg *packages.Package, xpath string) (any, error) {
 	case []ast.Node:
 		ns := make([]*ASTNode, len(v))
 		for i := range ns {
-			ns[i] = NewASTNode(pkg.TypesInfo, v[i])
+			ns[i] = k.u.ASTNode(pkg.TypesInfo, v[i])
 		}
 		return ns, nil
 	}
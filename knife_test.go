@@ -201,3 +201,13 @@ func TestTypeConversionFunctions(t *testing.T) {
 		})
 	}
 }
+
+// newTestKnife loads the packages with the option and fails the test on errors.
+func newTestKnife(t *testing.T, opt *KnifeOption, patterns ...string) *Knife {
+	t.Helper()
+	k, err := New(opt, patterns...)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	return k
+}
@@ -0,0 +1,221 @@
+package knife
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LayerRules is a set of layers and rules of dependencies between them.
+// It is read from a JSON file such as:
+//
+//	{
+//		"layers": [
+//			{"name": "domain", "packages": ["example.com/m/domain/..."]},
+//			{"name": "infra", "packages": ["example.com/m/infra/..."]},
+//			{"name": "app", "packages": ["example.com/m/app/...", "example.com/m/cmd/..."]}
+//		],
+//		"rules": [
+//			{"from": "domain", "deny": ["infra", "app"]},
+//			{"from": "infra", "allow": ["domain"]}
+//		]
+//	}
+//
+// A package belongs to the first layer which has a matching pattern.
+// Imports in a same layer and imports of packages which belong to no layer are always allowed.
+type LayerRules struct {
+	Layers []*Layer     `json:"layers"`
+	Rules  []*LayerRule `json:"rules"`
+}
+
+// Layer is a group of packages.
+type Layer struct {
+	Name string `json:"name"`
+	// Packages are package patterns such as "example.com/m/domain/...".
+	// "..." matches any string as well as go list.
+	Packages []string `json:"packages"`
+}
+
+// LayerRule is a rule of dependencies from a layer.
+type LayerRule struct {
+	From string `json:"from"`
+	// Allow is a list of layers which may be imported.
+	// If it is not nil, all other layers are forbidden.
+	Allow []string `json:"allow,omitempty"`
+	// Deny is a list of layers which must not be imported.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// ReadLayerRules reads rules from JSON and validates them.
+func ReadLayerRules(r io.Reader) (*LayerRules, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var rules LayerRules
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("cannot decode layer rules: %w", err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+func (rules *LayerRules) validate() error {
+	names := make(map[string]bool)
+	for _, l := range rules.Layers {
+		if l.Name == "" {
+			return fmt.Errorf("a layer must have a name")
+		}
+
+		if names[l.Name] {
+			return fmt.Errorf("layer %s is defined more than once", l.Name)
+		}
+		names[l.Name] = true
+
+		if len(l.Packages) == 0 {
+			return fmt.Errorf("layer %s has no packages", l.Name)
+		}
+	}
+
+	for _, r := range rules.Rules {
+		for _, name := range slices.Concat([]string{r.From}, r.Allow, r.Deny) {
+			if !names[name] {
+				return fmt.Errorf("rule refers to undefined layer %q", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Layer returns the layer of the package path.
+// It returns nil if the package belongs to no layer.
+func (rules *LayerRules) Layer(path string) *Layer {
+	for _, l := range rules.Layers {
+		for _, pattern := range l.Packages {
+			if matchPattern(pattern, path) {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+// Allowed reports whether a package in the layer from may import a package in the layer to.
+func (rules *LayerRules) Allowed(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	for _, r := range rules.Rules {
+		if r.From != from {
+			continue
+		}
+
+		if r.Allow != nil && !slices.Contains(r.Allow, to) {
+			return false
+		}
+
+		if slices.Contains(r.Deny, to) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchPattern reports whether the package path matches the pattern.
+// "..." in the pattern matches any string and "x/..." also matches "x".
+func matchPattern(pattern, path string) bool {
+	if !strings.Contains(pattern, "...") {
+		return pattern == path
+	}
+
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\.\.\.`, `.*`)
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+	return regexp.MustCompile("^" + re + "$").MatchString(path)
+}
+
+// LayerViolation is an import which violates the layer rules.
+type LayerViolation struct {
+	From      *Package
+	To        *Package
+	FromLayer string
+	ToLayer   string
+	pos       token.Pos
+}
+
+var _ fmt.Stringer = (*LayerViolation)(nil)
+
+// Pos returns a position of the import spec.
+func (v *LayerViolation) Pos() token.Pos {
+	return v.pos
+}
+
+func (v *LayerViolation) String() string {
+	return fmt.Sprintf("%s (%s) must not import %s (%s)", v.From.Path, v.FromLayer, v.To.Path, v.ToLayer)
+}
+
+// CheckLayers returns imports of pkgs which violate the rules.
+// The violations are sorted by their positions.
+func (u *Universe) CheckLayers(pkgs []*packages.Package, rules *LayerRules) []*LayerViolation {
+	if len(pkgs) == 0 {
+		return nil
+	}
+
+	var vs []*LayerViolation
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+
+		from := u.Package(pkg.Types)
+		fromLayer := rules.Layer(from.Path)
+		if fromLayer == nil {
+			continue
+		}
+
+		for _, imp := range from.Imports() {
+			toLayer := rules.Layer(imp.Path)
+			if toLayer == nil || rules.Allowed(fromLayer.Name, toLayer.Name) {
+				continue
+			}
+
+			vs = append(vs, &LayerViolation{
+				From:      from,
+				To:        imp,
+				FromLayer: fromLayer.Name,
+				ToLayer:   toLayer.Name,
+				pos:       importSpecPos(pkg, imp.TypesPackage),
+			})
+		}
+	}
+
+	fset := pkgs[0].Fset
+	slices.SortStableFunc(vs, func(a, b *LayerViolation) int {
+		pa, pb := fset.Position(a.pos), fset.Position(b.pos)
+		return cmp.Or(
+			cmp.Compare(pa.Filename, pb.Filename),
+			cmp.Compare(pa.Offset, pb.Offset),
+			cmp.Compare(a.String(), b.String()),
+		)
+	})
+
+	// a file may be loaded as several test variants
+	return slices.CompactFunc(vs, func(a, b *LayerViolation) bool {
+		return a.String() == b.String() && fset.Position(a.pos) == fset.Position(b.pos)
+	})
+}
@@ -0,0 +1,108 @@
+package knife
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const layerRules = `{
+	"layers": [
+		{"name": "app", "packages": ["github.com/gostaticanalysis/knife/testdata/why/app"]},
+		{"name": "core", "packages": ["github.com/gostaticanalysis/knife/testdata/why/l..."]},
+		{"name": "heavy", "packages": ["github.com/gostaticanalysis/knife/testdata/why/heavy/..."]}
+	],
+	"rules": [
+		{"from": "core", "deny": ["heavy"]},
+		{"from": "app", "allow": ["core"]}
+	]
+}`
+
+func TestKnife_CheckLayers(t *testing.T) {
+	rules, err := ReadLayerRules(strings.NewReader(layerRules))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	k := newTestKnife(t, &KnifeOption{Tests: true}, "./testdata/why/...")
+
+	var got []string
+	for _, v := range k.CheckLayers(rules) {
+		pos := k.Position(v)
+		got = append(got, fmt.Sprintf("%s:%d:%d %s -> %s", filepath.Base(pos.Filename), pos.Line, pos.Column, v.FromLayer, v.ToLayer))
+	}
+
+	want := []string{"l1.go:3:8 core -> heavy", "l2.go:4:2 core -> heavy"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLayerRules(t *testing.T) {
+	rules, err := ReadLayerRules(strings.NewReader(layerRules))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := []struct {
+		path  string
+		layer string
+	}{
+		{path: "github.com/gostaticanalysis/knife/testdata/why/app", layer: "app"},
+		{path: "github.com/gostaticanalysis/knife/testdata/why/app/sub", layer: ""},
+		{path: "github.com/gostaticanalysis/knife/testdata/why/l1", layer: "core"},
+		{path: "github.com/gostaticanalysis/knife/testdata/why/heavy", layer: "heavy"},
+		{path: "github.com/gostaticanalysis/knife/testdata/why/heavy/sub", layer: "heavy"},
+		{path: "github.com/gostaticanalysis/knife/testdata/why/heavysub", layer: ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.path, func(t *testing.T) {
+			var got string
+			if l := rules.Layer(tt.path); l != nil {
+				got = l.Name
+			}
+
+			if got != tt.layer {
+				t.Errorf("got %q, want %q", got, tt.layer)
+			}
+		})
+	}
+
+	allowed := []struct {
+		from, to string
+		want     bool
+	}{
+		{from: "core", to: "core", want: true},
+		{from: "core", to: "heavy", want: false},
+		{from: "core", to: "app", want: true},
+		{from: "app", to: "core", want: true},
+		{from: "app", to: "heavy", want: false},
+		{from: "heavy", to: "app", want: true},
+	}
+
+	for _, tt := range allowed {
+		if got := rules.Allowed(tt.from, tt.to); got != tt.want {
+			t.Errorf("Allowed(%s, %s): got %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestReadLayerRules_Error(t *testing.T) {
+	cases := map[string]string{
+		"unknown field":   `{"layers": [], "groups": []}`,
+		"undefined layer": `{"layers": [{"name": "a", "packages": ["a"]}], "rules": [{"from": "a", "deny": ["b"]}]}`,
+		"duplicated":      `{"layers": [{"name": "a", "packages": ["a"]}, {"name": "a", "packages": ["b"]}]}`,
+		"no packages":     `{"layers": [{"name": "a"}]}`,
+	}
+
+	for name, rules := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadLayerRules(strings.NewReader(rules)); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
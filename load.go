@@ -0,0 +1,67 @@
+package knife
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadStrategy is a strategy for loading packages.
+type LoadStrategy int
+
+const (
+	// LoadAllSyntax parses and type-checks the packages
+	// and all of their dependencies from source.
+	LoadAllSyntax LoadStrategy = iota
+	// LoadRootSyntax parses and type-checks only the packages
+	// which match the patterns from source.
+	// Dependencies are loaded from compiler export data,
+	// so the objects of the dependencies have types but no syntax.
+	LoadRootSyntax
+)
+
+var _ flag.Value = (*LoadStrategy)(nil)
+
+func (s *LoadStrategy) String() string {
+	if s == nil {
+		return ""
+	}
+
+	switch *s {
+	case LoadAllSyntax:
+		return "all"
+	case LoadRootSyntax:
+		return "root"
+	}
+	return fmt.Sprintf("LoadStrategy(%d)", int(*s))
+}
+
+// Set implements [flag.Value].
+func (s *LoadStrategy) Set(v string) error {
+	switch strings.ToLower(v) {
+	case "all":
+		*s = LoadAllSyntax
+	case "root":
+		*s = LoadRootSyntax
+	default:
+		return fmt.Errorf("unknown load strategy %q: expected all or root", v)
+	}
+	return nil
+}
+
+// Mode returns [packages.LoadMode] for the strategy.
+func (s LoadStrategy) Mode() packages.LoadMode {
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+		packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports |
+		packages.NeedForTest | packages.NeedModule
+
+	// without NeedDeps, go/packages type-checks only root packages
+	// and imports their dependencies from export data
+	if s == LoadAllSyntax {
+		mode |= packages.NeedDeps
+	}
+
+	return mode
+}
@@ -0,0 +1,103 @@
+package knife
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLoadStrategy(t *testing.T) {
+	cases := []struct {
+		name     string
+		strategy LoadStrategy
+		needDeps bool
+	}{
+		{name: "all", strategy: LoadAllSyntax, needDeps: true},
+		{name: "root", strategy: LoadRootSyntax, needDeps: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := tt.strategy.Mode()
+			if got := mode&packages.NeedDeps != 0; got != tt.needDeps {
+				t.Errorf("NeedDeps: got %v, want %v", got, tt.needDeps)
+			}
+
+			opt := &KnifeOption{Load: tt.strategy}
+			k := newTestKnife(t, opt, "./testdata/buildctx")
+
+			pkg := k.Packages()[0]
+			if len(pkg.Syntax) == 0 || pkg.TypesInfo == nil {
+				t.Error("root packages must be loaded from source")
+			}
+
+			var s LoadStrategy
+			if err := s.Set(tt.name); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if s != tt.strategy || s.String() != tt.name {
+				t.Errorf("flag value: got %v, want %v", s, tt.strategy)
+			}
+		})
+	}
+}
+
+func TestLoadStrategy_Dependencies(t *testing.T) {
+	skipIfExportDataUnsupported(t)
+
+	const tmpl = `{{typeof "io.Reader"}} {{(objectof "io.EOF").Type}} {{(objectof "github.com/gostaticanalysis/comment.Maps").Type.Underlying}}
+{{range .Imports}}{{.Path}}:{{range $name, $tn := .Types}}{{if $tn.Exported}}{{$name}},{{end}}{{end}}{{br}}{{end}}`
+
+	outputs := make(map[LoadStrategy]string)
+	for _, strategy := range []LoadStrategy{LoadAllSyntax, LoadRootSyntax} {
+		opt := &KnifeOption{Load: strategy}
+		k := newTestKnife(t, opt, "./testdata/load/a")
+
+		pkg := k.Packages()[0]
+		for path, imp := range pkg.Imports {
+			if got, want := len(imp.Syntax) != 0, strategy == LoadAllSyntax; got != want {
+				t.Errorf("%s: syntax of %s: got %v, want %v", &strategy, path, got, want)
+			}
+		}
+
+		var buf strings.Builder
+		if err := k.Execute(&buf, pkg, tmpl, &ExecuteOption{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		outputs[strategy] = buf.String()
+	}
+
+	all, root := outputs[LoadAllSyntax], outputs[LoadRootSyntax]
+	if all != root {
+		t.Errorf("dependencies must be same: all %q, root %q", all, root)
+	}
+
+	lines := strings.Split(all, "\n")
+	if got, want := lines[0], "io.Reader error []go/ast.CommentMap"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if !slices.ContainsFunc(lines, func(line string) bool {
+		return strings.HasPrefix(line, "io:") && strings.Contains(line, ",Reader,")
+	}) {
+		t.Errorf("types of io must be loaded: %q", lines)
+	}
+}
+
+// skipIfExportDataUnsupported skips the test if golang.org/x/tools cannot read
+// export data of the Go toolchain, which is required by [LoadRootSyntax].
+func skipIfExportDataUnsupported(t *testing.T) {
+	t.Helper()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedTypes}, "io")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(pkgs) != 1 || len(pkgs[0].Errors) != 0 {
+		t.Skip("export data of the toolchain is not supported:", pkgs[0].Errors)
+	}
+}
@@ -0,0 +1,283 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+)
+
+// Platform is a pair of GOOS and GOARCH.
+type Platform struct {
+	GOOS   string
+	GOARCH string
+}
+
+var _ fmt.Stringer = Platform{}
+
+func (p Platform) String() string {
+	return p.GOOS + "/" + p.GOARCH
+}
+
+// ParsePlatforms parses a comma-separated list of GOOS/GOARCH pairs
+// such as "linux/amd64,windows/amd64".
+func ParsePlatforms(s string) ([]Platform, error) {
+	var platforms []Platform
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		goos, goarch, ok := strings.Cut(v, "/")
+		if !ok || goos == "" || goarch == "" {
+			return nil, fmt.Errorf("invalid platform %q: expected GOOS/GOARCH", v)
+		}
+		platforms = append(platforms, Platform{GOOS: goos, GOARCH: goarch})
+	}
+
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("no platform is specified")
+	}
+
+	return platforms, nil
+}
+
+// NewMatrix loads the packages for each platform and creates a [Knife]
+// which holds merged packages.
+// Each object of the merged packages has Platforms field which reports
+// the platforms on which the object exists.
+func NewMatrix(opt *KnifeOption, platforms []Platform, patterns ...string) (*Knife, error) {
+	if opt == nil {
+		opt = &KnifeOption{Tests: true}
+	}
+
+	if opt.CallGraph != CallGraphNone {
+		return nil, fmt.Errorf("a call graph cannot be built with multiple platforms")
+	}
+
+	fset := opt.Fset
+	if fset == nil {
+		fset = token.NewFileSet()
+	}
+
+	u := opt.Universe
+	if u == nil {
+		u = NewUniverse()
+	}
+
+	knives := make([]*Knife, len(platforms))
+	knifePkgs := make([][]*Package, len(platforms))
+	for i, p := range platforms {
+		popt := *opt
+		popt.Fset = fset
+		popt.Universe = u
+		popt.GOOS, popt.GOARCH = p.GOOS, p.GOARCH
+		k, err := New(&popt, patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		knives[i] = k
+		knifePkgs[i] = k.KnifePackages()
+	}
+
+	merged := MergePlatforms(platforms, knifePkgs)
+
+	m := &Knife{
+		fset:      fset,
+		ins:       make(map[*packages.Package]*inspector.Inspector),
+		merged:    make(map[*packages.Package]*Package, len(merged)),
+		platforms: platforms,
+		u:         u,
+	}
+
+	for _, pkg := range merged {
+		// the first loaded package is a representative of the merged package
+		for _, k := range knives {
+			i := slices.IndexFunc(k.pkgs, func(p *packages.Package) bool {
+				return p.Types == pkg.TypesPackage
+			})
+			if i == -1 {
+				continue
+			}
+			p := k.pkgs[i]
+			m.pkgs = append(m.pkgs, p)
+			m.ins[p] = k.ins[p]
+			m.merged[p] = pkg
+			break
+		}
+	}
+
+	return m, nil
+}
+
+// Platforms returns platforms which are given to [NewMatrix].
+func (k *Knife) Platforms() []Platform {
+	return k.platforms
+}
+
+// MergePlatforms merges packages which are loaded for each platform.
+// pkgs[i] must be the packages which are loaded for platforms[i].
+// Packages are merged by their paths and objects are merged by their names.
+// The merged packages and objects are the first found ones and
+// their Platforms fields are set.
+func MergePlatforms(platforms []Platform, pkgs [][]*Package) []*Package {
+	var (
+		merged []*Package
+		byPath = make(map[string]*Package)
+	)
+
+	for i, ps := range pkgs {
+		platform := platforms[i]
+		for _, pkg := range ps {
+			if pkg == nil {
+				continue
+			}
+
+			m := byPath[pkg.Path]
+			if m == nil {
+				m = clonePackage(pkg)
+				byPath[pkg.Path] = m
+				merged = append(merged, m)
+			}
+
+			objs := &m.objects
+			mergeObjects(objs.funcs, pkg.Funcs(), platform)
+			mergeObjects(objs.vars, pkg.Vars(), platform)
+			mergeObjects(objs.consts, pkg.Consts(), platform)
+			mergeObjects(objs.types, pkg.Types(), platform)
+
+			for _, imp := range pkg.Imports() {
+				if !slices.ContainsFunc(m.imports, func(p *Package) bool { return p.Path == imp.Path }) {
+					m.imports = append(m.imports, imp)
+				}
+			}
+		}
+	}
+
+	for _, m := range merged {
+		objs := &m.objects
+		objs.funcNames = slices.Sorted(maps.Keys(objs.funcs))
+		objs.varNames = slices.Sorted(maps.Keys(objs.vars))
+		objs.constNames = slices.Sorted(maps.Keys(objs.consts))
+		objs.typeNames = slices.Sorted(maps.Keys(objs.types))
+	}
+
+	return merged
+}
+
+// clonePackage creates a package which has same information as pkg
+// and empty objects to be merged.
+func clonePackage(pkg *Package) *Package {
+	m := &Package{
+		TypesPackage:  pkg.TypesPackage,
+		Name:          pkg.Name,
+		Path:          pkg.Path,
+		Errors:        pkg.Errors,
+		IllTyped:      pkg.IllTyped,
+		ForTest:       pkg.ForTest,
+		IsTestVariant: pkg.IsTestVariant,
+		Module:        pkg.Module,
+		u:             pkg.u,
+	}
+
+	m.importsOnce.Do(func() {
+		m.imports = slices.Clone(pkg.Imports())
+	})
+
+	m.objectsOnce.Do(func() {
+		m.objects = packageObjects{
+			funcs:  make(map[string]*Func),
+			vars:   make(map[string]*Var),
+			consts: make(map[string]*Const),
+			types:  make(map[string]*TypeName),
+		}
+	})
+
+	return m
+}
+
+type platformObject interface {
+	Object
+	platforms() *[]Platform
+}
+
+func mergeObjects[O platformObject](dst, src map[string]O, platform Platform) {
+	for name, obj := range src {
+		m, ok := dst[name]
+		if !ok {
+			m = obj
+			dst[name] = m
+		}
+		ps := m.platforms()
+		if !slices.Contains(*ps, platform) {
+			*ps = append(*ps, platform)
+		}
+	}
+}
+
+// PlatformReport writes objects which exist on only some of the platforms.
+// Each line has a qualified object name, the platforms on which the object exists
+// and the platforms on which the object is missing.
+// Unexported objects are not reported.
+func PlatformReport(w io.Writer, platforms []Platform, pkgs []*Package) error {
+	type entry struct {
+		name string
+		obj  platformObject
+	}
+
+	for _, pkg := range slices.SortedFunc(slices.Values(pkgs), func(a, b *Package) int {
+		return cmp.Compare(a.Path, b.Path)
+	}) {
+		var entries []entry
+		for name, obj := range pkg.Objects() {
+			if o, ok := obj.(platformObject); ok {
+				entries = append(entries, entry{name: name, obj: o})
+			}
+		}
+		for name, obj := range pkg.Types() {
+			entries = append(entries, entry{name: name, obj: obj})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return cmp.Compare(a.name, b.name)
+		})
+
+		for _, e := range entries {
+			if !e.obj.TypesObject().Exported() {
+				continue
+			}
+
+			on := *e.obj.platforms()
+			if len(on) == len(platforms) {
+				continue
+			}
+
+			var missing []Platform
+			for _, p := range platforms {
+				if !slices.Contains(on, p) {
+					missing = append(missing, p)
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "%s.%s\t%s\tmissing: %s\n", pkg.Path, e.name, joinPlatforms(on), joinPlatforms(missing)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinPlatforms(platforms []Platform) string {
+	ss := make([]string, len(platforms))
+	for i := range platforms {
+		ss[i] = platforms[i].String()
+	}
+	return strings.Join(ss, ",")
+}
@@ -0,0 +1,97 @@
+package knife
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       string
+		want    []Platform
+		wantErr bool
+	}{
+		{
+			name: "single",
+			s:    "linux/amd64",
+			want: []Platform{{GOOS: "linux", GOARCH: "amd64"}},
+		},
+		{
+			name: "multiple",
+			s:    "linux/amd64, windows/arm64",
+			want: []Platform{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "windows", GOARCH: "arm64"}},
+		},
+		{
+			name:    "no arch",
+			s:       "linux",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			s:       "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlatforms(tt.s)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expected error but got nil")
+			case !tt.wantErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMatrix(t *testing.T) {
+	linux := Platform{GOOS: "linux", GOARCH: "amd64"}
+	windows := Platform{GOOS: "windows", GOARCH: "amd64"}
+	platforms := []Platform{linux, windows}
+
+	k, err := NewMatrix(nil, platforms, "./testdata/buildctx")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	pkgs := k.KnifePackages()
+	if len(pkgs) != 1 {
+		t.Fatalf("the number of packages must be 1 but %d", len(pkgs))
+	}
+
+	funcs := pkgs[0].Funcs()
+	if got := funcs["Common"].Platforms; !slices.Equal(got, platforms) {
+		t.Errorf("Common: got %v, want %v", got, platforms)
+	}
+
+	if got, want := funcs["Windows"].Platforms, []Platform{windows}; !slices.Equal(got, want) {
+		t.Errorf("Windows: got %v, want %v", got, want)
+	}
+
+	var buf strings.Builder
+	if err := PlatformReport(&buf, k.Platforms(), pkgs); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	want := "github.com/gostaticanalysis/knife/testdata/buildctx.Windows\twindows/amd64\tmissing: linux/amd64\n"
+	if got := buf.String(); got != want {
+		t.Errorf("report: got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := k.Execute(&buf, k.Packages()[0], `{{range .FuncNames}}{{.}} {{end}}`, &ExecuteOption{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got, want := buf.String(), "Common Windows "; got != want {
+		t.Errorf("execute: got %q, want %q", got, want)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/gostaticanalysis/knife"
 	"github.com/gostaticanalysis/knife/cutter"
 )
 
@@ -18,20 +20,24 @@ type CutterInput struct {
 	Patterns []string `json:"patterns"`         // Package patterns to analyze (e.g., ["fmt", "net/http"])
 	Format   string   `json:"format,omitempty"` // Template string for output formatting
 	Data     string   `json:"data,omitempty"`   // Extra data as key:value pairs
+	Strict   bool     `json:"strict,omitempty"` // Fail if packages have load, parse or type errors
+	All      bool     `json:"all,omitempty"`    // Execute the template once with all packages
 }
 
 // CutterOutput represents the output from the cutter MCP tool.
 // It contains the formatted analysis results as structured JSON.
 type CutterOutput struct {
-	Success bool            `json:"success"`         // Whether the operation succeeded
-	Results []PackageResult `json:"results"`         // Analysis results per package
-	Error   string          `json:"error,omitempty"` // Error message if any
+	Success bool            `json:"success"`           // Whether the operation succeeded
+	Results []PackageResult `json:"results"`           // Analysis results per package
+	Content string          `json:"content,omitempty"` // Formatted template output of all packages (all mode)
+	Error   string          `json:"error,omitempty"`   // Error message if any
 }
 
 // PackageResult represents the analysis result for a single package.
 type PackageResult struct {
-	PackageName string `json:"package_name"` // Name of the analyzed package
-	Content     string `json:"content"`      // Formatted template output
+	PackageName string         `json:"package_name"`     // Name of the analyzed package
+	Content     string         `json:"content"`          // Formatted template output
+	Errors      []*knife.Error `json:"errors,omitempty"` // Load, parse and type errors of the package
 }
 
 // newCutterTool creates the cutter MCP tool.
@@ -44,6 +50,8 @@ func newCutterTool() *mcp.ServerTool {
 			mcp.Property("patterns", mcp.Description("Package patterns to analyze (e.g., [\"fmt\", \"net/http\", \"./...\"])"), mcp.Required(true)),
 			mcp.Property("format", mcp.Description(formatDesc)),
 			mcp.Property("data", mcp.Description("Extra data as key:value pairs (e.g., \"key1:value1,key2:value2\")")),
+			mcp.Property("strict", mcp.Description("Fail if packages have load, parse or type errors")),
+			mcp.Property("all", mcp.Description("Execute the template once with a program which has all packages (.Packages, .Types and .Funcs by qualified names)")),
 		),
 	)
 }
@@ -57,8 +65,20 @@ func cutterHandler(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallT
 	}
 
 	// Create cutter instance
-	cutterOpt := &cutter.CutterOption{Tests: true}
+	// strict mode also fails on errors of dependencies
+	cutterOpt := &cutter.CutterOption{Tests: true, Strict: input.Strict}
 	c, err := cutter.New(cutterOpt, input.Patterns...)
+	var lerr *knife.LoadError
+	if errors.As(err, &lerr) {
+		return &mcp.CallToolResultFor[CutterOutput]{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: mustMarshalJSON(CutterOutput{
+					Success: false,
+					Error:   fmt.Sprintf("packages have load, parse or type errors: %q", lerr.Error()),
+				}),
+			}},
+		}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cutter: %w", err)
 	}
@@ -82,12 +102,36 @@ func cutterHandler(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallT
 		format = "{{.}}"
 	}
 
+	// Execute cutter for all packages at once
+	var all bytes.Buffer
+	if input.All {
+		opt := &cutter.Option{ExtraData: extraData}
+		if err := c.ExecuteAll(&all, format, opt); err != nil {
+			return &mcp.CallToolResultFor[CutterOutput]{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: mustMarshalJSON(CutterOutput{
+						Success: false,
+						Error:   fmt.Sprintf("failed to execute cutter for all packages: %q", err.Error()),
+					}),
+				}},
+			}, nil
+		}
+	}
+
 	// Execute cutter for each package
 	pkgs := c.KnifePackages()
 	results := make([]PackageResult, 0, len(pkgs))
 
 	for _, pkg := range pkgs {
 		var buf bytes.Buffer
+		if input.All {
+			results = append(results, PackageResult{
+				PackageName: pkg.Path,
+				Errors:      pkg.Errors,
+			})
+			continue
+		}
+
 		opt := &cutter.Option{ExtraData: extraData}
 		if err := c.Execute(&buf, pkg, format, opt); err != nil {
 			return &mcp.CallToolResultFor[CutterOutput]{
@@ -105,12 +149,14 @@ func cutterHandler(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallT
 		results = append(results, PackageResult{
 			PackageName: pkg.Path,
 			Content:     content,
+			Errors:      pkg.Errors,
 		})
 	}
 
 	output := CutterOutput{
 		Success: true,
 		Results: results,
+		Content: all.String(),
 	}
 
 	return &mcp.CallToolResultFor[CutterOutput]{
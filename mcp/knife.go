@@ -3,6 +3,7 @@ package mcp
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -14,18 +15,24 @@ import (
 // It defines the package patterns to analyze, optional template formatting,
 // extra data for template evaluation, and XPath filtering for AST nodes.
 type KnifeInput struct {
-	Patterns []string `json:"patterns"`         // Package patterns to analyze (e.g., ["fmt", "net/http"])
-	Format   string   `json:"format,omitempty"` // Template string for output formatting
-	Data     string   `json:"data,omitempty"`   // Extra data as key:value pairs
-	XPath    string   `json:"xpath,omitempty"`  // XPath expression for AST node filtering
+	Patterns  []string `json:"patterns"`            // Package patterns to analyze (e.g., ["fmt", "net/http"])
+	Format    string   `json:"format,omitempty"`    // Template string for output formatting
+	Data      string   `json:"data,omitempty"`      // Extra data as key:value pairs
+	XPath     string   `json:"xpath,omitempty"`     // XPath expression for AST node filtering
+	Strict    bool     `json:"strict,omitempty"`    // Fail if packages have load, parse or type errors
+	All       bool     `json:"all,omitempty"`       // Execute the template once with all packages
+	CallGraph string   `json:"callgraph,omitempty"` // Algorithm to build a call graph (none, cha or vta)
 }
 
 // KnifeOutput represents the output from the knife MCP tool.
 // It contains the formatted analysis results as structured JSON.
 type KnifeOutput struct {
-	Success bool            `json:"success"`         // Whether the operation succeeded
-	Results []PackageResult `json:"results"`         // Analysis results per package
-	Error   string          `json:"error,omitempty"` // Error message if any
+	Success bool            `json:"success"`           // Whether the operation succeeded
+	Results []PackageResult `json:"results"`           // Analysis results per package
+	Content string          `json:"content,omitempty"` // Formatted template output of all packages (all mode)
+	// Diagnostics are reported by diag and report in the template as "file:line:col: message"
+	Diagnostics []string `json:"diagnostics,omitempty"`
+	Error       string   `json:"error,omitempty"` // Error message if any
 }
 
 // newKnifeTool creates the knife MCP tool.
@@ -39,6 +46,9 @@ func newKnifeTool() *mcp.ServerTool {
 			mcp.Property("format", mcp.Description(formatDesc)),
 			mcp.Property("data", mcp.Description("Extra data as key:value pairs (e.g., \"key1:value1,key2:value2\")")),
 			mcp.Property("xpath", mcp.Description("XPath expression for AST node filtering")),
+			mcp.Property("strict", mcp.Description("Fail if packages have load, parse or type errors")),
+			mcp.Property("all", mcp.Description("Execute the template once with a program which has all packages (.Packages, .Types and .Funcs by qualified names)")),
+			mcp.Property("callgraph", mcp.Description("Algorithm to build a call graph for callers, callees and reaches (none, cha or vta)")),
 		),
 	)
 }
@@ -52,15 +62,36 @@ func knifeHandler(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallTo
 	}
 
 	// Create knife instance
-	knifeOpt := &knife.KnifeOption{Tests: true}
+	// strict mode also fails on errors of dependencies
+	knifeOpt := &knife.KnifeOption{Tests: true, Strict: input.Strict}
+	if input.CallGraph != "" {
+		if err := knifeOpt.CallGraph.Set(input.CallGraph); err != nil {
+			return nil, err
+		}
+	}
 	k, err := knife.New(knifeOpt, input.Patterns...)
+	var lerr *knife.LoadError
+	if errors.As(err, &lerr) {
+		return &mcp.CallToolResultFor[KnifeOutput]{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: mustMarshalJSON(KnifeOutput{
+					Success: false,
+					Error:   fmt.Sprintf("packages have load, parse or type errors: %q", lerr.Error()),
+				}),
+			}},
+		}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create knife: %w", err)
 	}
 
 	// Set up options
+	var diags []string
 	opt := &knife.ExecuteOption{
 		XPath: input.XPath,
+		Report: func(d *knife.Diagnostic) {
+			diags = append(diags, d.String())
+		},
 	}
 
 	// Parse extra data if provided
@@ -85,12 +116,35 @@ func knifeHandler(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallTo
 		format = "{{.}}"
 	}
 
+	// Execute knife for all packages at once
+	var all bytes.Buffer
+	if input.All {
+		if err := k.ExecuteAll(&all, format, opt); err != nil {
+			return &mcp.CallToolResultFor[KnifeOutput]{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: mustMarshalJSON(KnifeOutput{
+						Success: false,
+						Error:   fmt.Sprintf("failed to execute knife for all packages: %q", err.Error()),
+					}),
+				}},
+			}, nil
+		}
+	}
+
 	// Execute knife for each package
 	pkgs := k.Packages()
 	results := make([]PackageResult, 0, len(pkgs))
 
 	for _, pkg := range pkgs {
 		var buf bytes.Buffer
+		if input.All {
+			results = append(results, PackageResult{
+				PackageName: pkg.PkgPath,
+				Errors:      knife.PackageErrors(pkg),
+			})
+			continue
+		}
+
 		if err := k.Execute(&buf, pkg, format, opt); err != nil {
 			return &mcp.CallToolResultFor[KnifeOutput]{
 				Content: []mcp.Content{&mcp.TextContent{
@@ -107,12 +161,15 @@ func knifeHandler(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallTo
 		results = append(results, PackageResult{
 			PackageName: pkg.PkgPath,
 			Content:     content,
+			Errors:      knife.PackageErrors(pkg),
 		})
 	}
 
 	output := KnifeOutput{
-		Success: true,
-		Results: results,
+		Success:     true,
+		Results:     results,
+		Content:     all.String(),
+		Diagnostics: diags,
 	}
 
 	return &mcp.CallToolResultFor[KnifeOutput]{
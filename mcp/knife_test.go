@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -107,3 +108,38 @@ func TestKnifeHandler_ValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestKnifeHandler_Strict(t *testing.T) {
+	cases := []struct {
+		name   string
+		strict bool
+		want   bool
+	}{
+		{name: "strict", strict: true, want: false},
+		{name: "tolerant", strict: false, want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// only the dependency has errors
+			params := &mcp.CallToolParamsFor[KnifeInput]{
+				Arguments: KnifeInput{Patterns: []string{"../testdata/brokendep"}, Strict: tc.strict},
+			}
+
+			result, err := knifeHandler(context.Background(), nil, params)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			var output KnifeOutput
+			text := result.Content[0].(*mcp.TextContent).Text
+			if err := json.Unmarshal([]byte(text), &output); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if output.Success != tc.want {
+				t.Errorf("success must be %v but %s", tc.want, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package knife
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Member is a field or a method which can be selected from a type
+// including a promoted member through embedded fields.
+type Member struct {
+	// Name is the name of the field or the method.
+	Name string
+	// Field is the field if the member is a field.
+	Field *Field
+	// Method is the method if the member is a method.
+	Method *Func
+	// Embeddeds are the embedded fields through which the member is promoted.
+	// It is empty for a member which is declared in the type itself.
+	Embeddeds []*Field
+	// Pointer reports whether the member requires *T, which means
+	// the method has a pointer receiver and it is not promoted through an embedded pointer.
+	Pointer bool
+	// Shadowed reports whether the member is shadowed by a member
+	// with the same name at a shallower depth.
+	Shadowed bool
+	// Ambiguous reports whether there are other members with the same name at the same depth.
+	// An ambiguous member cannot be selected.
+	Ambiguous bool
+}
+
+var _ fmt.Stringer = (*Member)(nil)
+
+// Depth returns the number of embedded fields through which the member is promoted.
+func (m *Member) Depth() int {
+	return len(m.Embeddeds)
+}
+
+// Path returns the selector path of the member such as "Base.Logger.Printf".
+func (m *Member) Path() string {
+	names := make([]string, 0, len(m.Embeddeds)+1)
+	for _, f := range m.Embeddeds {
+		names = append(names, f.Name)
+	}
+	return strings.Join(append(names, m.Name), ".")
+}
+
+// Receiver returns "*T" if the member requires *T, otherwise "T".
+func (m *Member) Receiver() string {
+	if m.Pointer {
+		return "*T"
+	}
+	return "T"
+}
+
+func (m *Member) String() string {
+	return m.Path()
+}
+
+// PromotedFields returns fields of the struct type in a new [Universe].
+// See [Universe.PromotedFields].
+func PromotedFields(typ any) []*Member {
+	return NewUniverse().PromotedFields(typ)
+}
+
+// PromotedFields returns fields of the struct type including fields which are
+// promoted through embedded fields in breadth-first order.
+// Shadowed and ambiguous fields are also returned and they are marked.
+func (u *Universe) PromotedFields(typ any) []*Member {
+	var fields []*Member
+	for _, m := range u.members(typ) {
+		if m.Field != nil {
+			fields = append(fields, m)
+		}
+	}
+	return fields
+}
+
+// MethodSet returns methods of the type in a new [Universe].
+// See [Universe.MethodSet].
+func MethodSet(typ any) []*Member {
+	return NewUniverse().MethodSet(typ)
+}
+
+// MethodSet returns methods of T and *T of the type including methods which are
+// promoted through embedded structs and interfaces in breadth-first order.
+// A method which is only in the method set of *T is marked by Pointer.
+// Shadowed and ambiguous methods are also returned and they are marked.
+func (u *Universe) MethodSet(typ any) []*Member {
+	var methods []*Member
+	for _, m := range u.members(typ) {
+		if m.Method != nil {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// members returns all fields and methods of the type in breadth-first order
+// by the depth of embedding like the selector rule of the Go spec.
+func (u *Universe) members(typ any) []*Member {
+	t := unalias(typ)
+	if t == nil {
+		return nil
+	}
+
+	// methods of *T do not require *T
+	var indirect bool
+	if ptr, ok := t.(*types.Pointer); ok {
+		t, indirect = types.Unalias(ptr.Elem()), true
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	type embedding struct {
+		typ       types.Type
+		embeddeds []*Field
+		indirect  bool
+	}
+
+	var (
+		members []*Member
+		seen    = make(map[*types.Named]int) // depth where the type is found first
+		current = []embedding{{typ: t, indirect: indirect}}
+	)
+
+	for depth := 0; len(current) > 0; depth++ {
+		var next []embedding
+		depthStart := len(members)
+		for _, e := range current {
+			named, _ := e.typ.(*types.Named)
+			if named != nil {
+				// a type which is embedded at a shallower depth is shadowed entirely,
+				// but a type which is embedded twice at a same depth makes its members ambiguous
+				if d, ok := seen[named]; ok && d < depth {
+					continue
+				}
+				seen[named] = depth
+
+				for i := range named.NumMethods() {
+					m := named.Method(i)
+					_, ptrRecv := m.Type().(*types.Signature).Recv().Type().(*types.Pointer)
+					members = append(members, &Member{
+						Name:      m.Name(),
+						Method:    u.newFunc(m),
+						Embeddeds: e.embeddeds,
+						Pointer:   ptrRecv && !e.indirect,
+					})
+				}
+			}
+
+			switch typ := e.typ.Underlying().(type) {
+			case *types.Struct:
+				s := u.newStruct(typ)
+				for i := range typ.NumFields() {
+					v := typ.Field(i)
+					f := u.newField(s, v, typ.Tag(i))
+					members = append(members, &Member{
+						Name:      v.Name(),
+						Field:     f,
+						Embeddeds: e.embeddeds,
+					})
+
+					if v.Embedded() {
+						ft, indirect := types.Unalias(v.Type()), e.indirect
+						if ptr, ok := ft.(*types.Pointer); ok {
+							ft, indirect = types.Unalias(ptr.Elem()), true
+						}
+						next = append(next, embedding{
+							typ:       ft,
+							embeddeds: append(e.embeddeds[:len(e.embeddeds):len(e.embeddeds)], f),
+							indirect:  indirect,
+						})
+					}
+				}
+			case *types.Interface:
+				for i := range typ.NumMethods() {
+					m := typ.Method(i)
+					members = append(members, &Member{
+						Name:      m.Name(),
+						Method:    u.newFunc(m),
+						Embeddeds: e.embeddeds,
+					})
+				}
+			}
+		}
+
+		markSelectable(members, depthStart)
+		current = next
+	}
+
+	return members
+}
+
+// markSelectable marks members[depthStart:] which are at the same depth
+// as shadowed by members[:depthStart] or ambiguous with each other.
+func markSelectable(members []*Member, depthStart int) {
+	shallower := make(map[string]bool, depthStart)
+	for _, m := range members[:depthStart] {
+		shallower[m.Name] = true
+	}
+
+	count := make(map[string]int)
+	for _, m := range members[depthStart:] {
+		count[m.Name]++
+	}
+
+	for _, m := range members[depthStart:] {
+		switch {
+		case shallower[m.Name]:
+			m.Shadowed = true
+		case count[m.Name] > 1:
+			m.Ambiguous = true
+		}
+	}
+}
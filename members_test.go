@@ -0,0 +1,119 @@
+package knife
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMethodSet(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/members/a")
+	pkg := k.KnifePackages()[0]
+
+	cases := []struct {
+		typ  string
+		want []string
+	}{
+		{typ: "Service", want: []string{
+			"Close *T",
+			"Base.Describe T",
+			"Base.SetName *T ambiguous",
+			"Label.SetName *T ambiguous",
+			"Base.Logger.Printf T",
+		}},
+		{typ: "Handler", want: []string{
+			"Base.Describe T",
+			"Base.SetName T",
+			"Base.Logger.Printf T",
+		}},
+		{typ: "Logger", want: []string{
+			"Printf T",
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.typ, func(t *testing.T) {
+			got := memberStrings(MethodSet(pkg.Types()[tt.typ]))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotedFields(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/members/a")
+	pkg := k.KnifePackages()[0]
+
+	got := memberStrings(PromotedFields(pkg.Types()["Service"]))
+	want := []string{
+		"Base T",
+		"Label T",
+		"Name T",
+		"Base.Logger T",
+		"Base.ID T",
+		"Base.Name T shadowed",
+		"Label.Name T shadowed",
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if fields := PromotedFields(pkg.Types()["Logger"]); len(fields) != 0 {
+		t.Errorf("an interface does not have fields: %v", fields)
+	}
+}
+
+func memberStrings(members []*Member) []string {
+	strs := make([]string, len(members))
+	for i, m := range members {
+		strs[i] = m.Path() + " " + m.Receiver()
+		switch {
+		case m.Shadowed:
+			strs[i] += " shadowed"
+		case m.Ambiguous:
+			strs[i] += " ambiguous"
+		}
+	}
+	return strs
+}
+
+func TestTemplate_Members(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/members/a")
+
+	cases := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "methodSet",
+			tmpl: `{{range methodSet .Types.Service}}{{if not .Ambiguous}}{{.Path}}:{{.Receiver}}:{{.Method.Signature}} {{end}}{{end}}`,
+			want: "Close:*T:func() error Base.Describe:T:func() string Base.Logger.Printf:T:func(format string, args ...any) ",
+		},
+		{
+			name: "methodSet of pointer",
+			tmpl: `{{range methodSet (typeof "*a.Service")}}{{.Receiver}} {{end}}`,
+			want: "T T T T T ",
+		},
+		{
+			name: "promotedFields",
+			tmpl: `{{range promotedFields .Types.Service}}{{if not .Shadowed}}{{.Path}}:{{.Field.Type}}:{{.Depth}} {{end}}{{end}}`,
+			want: "Base:github.com/gostaticanalysis/knife/testdata/members/a.Base:0 Label:github.com/gostaticanalysis/knife/testdata/members/a.Label:0 Name:string:0 Base.Logger:github.com/gostaticanalysis/knife/testdata/members/a.Logger:1 Base.ID:int:1 ",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := k.Execute(&buf, k.Packages()[0], tt.tmpl, &ExecuteOption{}); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
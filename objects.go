@@ -12,18 +12,31 @@ type Object interface {
 	TypesObject() types.Object
 }
 
+// NewObject creates an [Object] in a new [Universe].
 func NewObject(o types.Object) Object {
+	return NewUniverse().Object(o)
+}
+
+// Object returns an [Object] of o in the universe.
+// It returns nil if o is a field or an unsupported object.
+func (u *Universe) Object(o types.Object) Object {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newObject(o)
+}
+
+func (u *Universe) newObject(o types.Object) Object {
 	switch o := o.(type) {
 	case *types.Var:
 		if !o.IsField() {
-			return NewVar(o)
+			return u.newVar(o)
 		}
 	case *types.Const:
-		return NewConst(o)
+		return u.newConst(o)
 	case *types.Func:
-		return NewFunc(o)
+		return u.newFunc(o)
 	case *types.TypeName:
-		return NewTypeName(o)
+		return u.newTypeName(o)
 	}
 	return nil
 }
@@ -41,28 +54,40 @@ type Field struct {
 var _ fmt.Stringer = (*Field)(nil)
 var _ Object = (*Field)(nil)
 
+// NewField creates a [Field] in the universe of s.
 func NewField(s *Struct, v *types.Var, tag string) *Field {
-	if s == nil || v == nil {
+	if s == nil {
 		return nil
 	}
 
-	_v, _ := cache.Load(v)
-	cached, _ := _v.(*Field)
-	if cached != nil {
-		return cached
+	u := s.u
+	if u == nil {
+		u = NewUniverse()
 	}
+	return u.Field(s, v, tag)
+}
+
+// Field returns a [Field] of v which belongs to s in the universe.
+func (u *Universe) Field(s *Struct, v *types.Var, tag string) *Field {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newField(s, v, tag)
+}
 
-	var nf Field
-	cache.Store(v, &nf)
-	nf.TypesVar = v
-	nf.Struct = s
-	nf.Tag = tag
-	nf.Anonymous = v.Anonymous()
-	nf.Exported = v.Exported()
-	nf.Name = v.Name()
-	nf.Type = NewType(v.Type())
+func (u *Universe) newField(s *Struct, v *types.Var, tag string) *Field {
+	if s == nil || v == nil {
+		return nil
+	}
 
-	return &nf
+	return load(u, v, func(nf *Field) {
+		nf.TypesVar = v
+		nf.Struct = s
+		nf.Tag = tag
+		nf.Anonymous = v.Anonymous()
+		nf.Exported = v.Exported()
+		nf.Name = v.Name()
+		nf.Type = u.newType(v.Type())
+	})
 }
 
 func (f *Field) Pos() token.Pos {
@@ -78,36 +103,41 @@ func (f *Field) TypesObject() types.Object {
 }
 
 type Var struct {
-	TypesVar *types.Var
-	Exported bool
-	Name     string
-	Type     *Type
-	Package  *Package
+	TypesVar  *types.Var
+	Exported  bool
+	Name      string
+	Type      *Type
+	Package   *Package
+	Platforms []Platform
 }
 
 var _ fmt.Stringer = (*Var)(nil)
 var _ Object = (*Var)(nil)
 
+// NewVar creates a [Var] in a new [Universe].
 func NewVar(v *types.Var) *Var {
+	return NewUniverse().Var(v)
+}
+
+// Var returns a [Var] of v in the universe.
+func (u *Universe) Var(v *types.Var) *Var {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newVar(v)
+}
+
+func (u *Universe) newVar(v *types.Var) *Var {
 	if v == nil {
 		return nil
 	}
 
-	_v, _ := cache.Load(v)
-	cached, _ := _v.(*Var)
-	if cached != nil {
-		return cached
-	}
-
-	var nv Var
-	cache.Store(v, &nv)
-	nv.TypesVar = v
-	nv.Exported = v.Exported()
-	nv.Name = v.Name()
-	nv.Type = NewType(v.Type())
-	nv.Package = NewPackage(v.Pkg())
-
-	return &nv
+	return load(u, v, func(nv *Var) {
+		nv.TypesVar = v
+		nv.Exported = v.Exported()
+		nv.Name = v.Name()
+		nv.Type = u.newType(v.Type())
+		nv.Package = u.newPackage(v.Pkg())
+	})
 }
 
 func (v *Var) Pos() token.Pos {
@@ -122,12 +152,17 @@ func (v *Var) TypesObject() types.Object {
 	return v.TypesVar
 }
 
+func (v *Var) platforms() *[]Platform {
+	return &v.Platforms
+}
+
 type Func struct {
 	TypesFunc *types.Func
 	Name      string
 	Exported  bool
 	Package   *Package
 	Signature *Signature
+	Platforms []Platform
 }
 
 var _ fmt.Stringer = (*Func)(nil)
@@ -145,26 +180,34 @@ func (f *Func) TypesObject() types.Object {
 	return f.TypesFunc
 }
 
+func (f *Func) platforms() *[]Platform {
+	return &f.Platforms
+}
+
+// NewFunc creates a [Func] in a new [Universe].
 func NewFunc(f *types.Func) *Func {
+	return NewUniverse().Func(f)
+}
+
+// Func returns a [Func] of f in the universe.
+func (u *Universe) Func(f *types.Func) *Func {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newFunc(f)
+}
+
+func (u *Universe) newFunc(f *types.Func) *Func {
 	if f == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(f)
-	cached, _ := v.(*Func)
-	if cached != nil {
-		return cached
-	}
-
-	var nf Func
-	cache.Store(f, &nf)
-	nf.TypesFunc = f
-	nf.Name = f.Name()
-	nf.Exported = f.Exported()
-	nf.Package = NewPackage(f.Pkg())
-	nf.Signature = NewSignature(f.Type().(*types.Signature))
-
-	return &nf
+	return load(u, f, func(nf *Func) {
+		nf.TypesFunc = f
+		nf.Name = f.Name()
+		nf.Exported = f.Exported()
+		nf.Package = u.newPackage(f.Pkg())
+		nf.Signature = u.newSignature(f.Type().(*types.Signature))
+	})
 }
 
 type TypeName struct {
@@ -174,6 +217,11 @@ type TypeName struct {
 	Name          string
 	Package       *Package
 	Type          *Type
+	Platforms     []Platform
+	// TypeParams are type parameters of a generic type.
+	TypeParams []*TypeParam
+	// Generic reports whether the type name declares a generic type.
+	Generic bool
 }
 
 var _ fmt.Stringer = (*TypeName)(nil)
@@ -191,27 +239,46 @@ func (tn *TypeName) TypesObject() types.Object {
 	return tn.TypesTypeName
 }
 
+func (tn *TypeName) platforms() *[]Platform {
+	return &tn.Platforms
+}
+
+// NewTypeName creates a [TypeName] in a new [Universe].
 func NewTypeName(tn *types.TypeName) *TypeName {
+	return NewUniverse().TypeName(tn)
+}
+
+// TypeName returns a [TypeName] of tn in the universe.
+func (u *Universe) TypeName(tn *types.TypeName) *TypeName {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newTypeName(tn)
+}
+
+func (u *Universe) newTypeName(tn *types.TypeName) *TypeName {
 	if tn == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(tn)
-	cached, _ := v.(*TypeName)
-	if cached != nil {
-		return cached
-	}
-
-	var ntn TypeName
-	cache.Store(tn, &ntn)
-	ntn.TypesTypeName = tn
-	ntn.Exported = tn.Exported()
-	ntn.IsAlias = tn.IsAlias()
-	ntn.Name = tn.Name()
-	ntn.Package = NewPackage(tn.Pkg())
-	ntn.Type = NewType(tn.Type())
-
-	return &ntn
+	return load(u, tn, func(ntn *TypeName) {
+		ntn.TypesTypeName = tn
+		ntn.Exported = tn.Exported()
+		ntn.IsAlias = tn.IsAlias()
+		ntn.Name = tn.Name()
+		ntn.Package = u.newPackage(tn.Pkg())
+		ntn.Type = u.newType(tn.Type())
+		switch typ := tn.Type().(type) {
+		case *types.Named:
+			if typ.Obj() == tn {
+				ntn.TypeParams = u.newTypeParams(typ.TypeParams())
+			}
+		case *types.Alias:
+			if typ.Obj() == tn {
+				ntn.TypeParams = u.newTypeParams(typ.TypeParams())
+			}
+		}
+		ntn.Generic = len(ntn.TypeParams) > 0
+	})
 }
 
 type Const struct {
@@ -221,6 +288,7 @@ type Const struct {
 	Package    *Package
 	Type       *Type
 	Value      constant.Value
+	Platforms  []Platform
 }
 
 var _ fmt.Stringer = (*Const)(nil)
@@ -238,27 +306,35 @@ func (c *Const) TypesObject() types.Object {
 	return c.TypesConst
 }
 
+func (c *Const) platforms() *[]Platform {
+	return &c.Platforms
+}
+
+// NewConst creates a [Const] in a new [Universe].
 func NewConst(c *types.Const) *Const {
+	return NewUniverse().Const(c)
+}
+
+// Const returns a [Const] of c in the universe.
+func (u *Universe) Const(c *types.Const) *Const {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newConst(c)
+}
+
+func (u *Universe) newConst(c *types.Const) *Const {
 	if c == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(c)
-	cached, _ := v.(*Const)
-	if cached != nil {
-		return cached
-	}
-
-	var nc Const
-	cache.Store(c, &nc)
-	nc.TypesConst = c
-	nc.Exported = c.Exported()
-	nc.Name = c.Name()
-	nc.Package = NewPackage(c.Pkg())
-	nc.Type = NewType(c.Type())
-	nc.Value = c.Val()
-
-	return &nc
+	return load(u, c, func(nc *Const) {
+		nc.TypesConst = c
+		nc.Exported = c.Exported()
+		nc.Name = c.Name()
+		nc.Package = u.newPackage(c.Pkg())
+		nc.Type = u.newType(c.Type())
+		nc.Value = c.Val()
+	})
 }
 
 func (c *Const) BoolVal() bool {
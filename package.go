@@ -5,25 +5,40 @@ import (
 	"go/types"
 	"iter"
 	"sync"
-)
 
-var (
-	cache sync.Map
+	"golang.org/x/tools/go/packages"
 )
 
+// Package is a package.
+// Imports and objects of the package are created on first access.
 type Package struct {
-	TypesPackage *types.Package
-	Name         string
-	Path         string
-	Imports      []*Package
-	Funcs        map[string]*Func
-	FuncNames    []string
-	Vars         map[string]*Var
-	VarNames     []string
-	Consts       map[string]*Const
-	ConstNames   []string
-	Types        map[string]*TypeName
-	TypeNames    []string
+	TypesPackage  *types.Package
+	Name          string
+	Path          string
+	Errors        []*Error
+	IllTyped      bool
+	ForTest       string
+	IsTestVariant bool
+	// Module is a path of the module which has the package.
+	// It is empty for a standard package or a dependency of the loaded packages.
+	Module string
+
+	u           *Universe
+	importsOnce sync.Once
+	imports     []*Package
+	objectsOnce sync.Once
+	objects     packageObjects
+}
+
+type packageObjects struct {
+	funcs      map[string]*Func
+	funcNames  []string
+	vars       map[string]*Var
+	varNames   []string
+	consts     map[string]*Const
+	constNames []string
+	types      map[string]*TypeName
+	typeNames  []string
 }
 
 var _ fmt.Stringer = (*Package)(nil)
@@ -32,70 +47,145 @@ func (pkg *Package) String() string {
 	return pkg.TypesPackage.String()
 }
 
+// NewPackage creates a [Package] in a new [Universe].
 func NewPackage(pkg *types.Package) *Package {
+	return NewUniverse().Package(pkg)
+}
+
+// Package returns a [Package] of pkg in the universe.
+func (u *Universe) Package(pkg *types.Package) *Package {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newPackage(pkg)
+}
+
+func (u *Universe) newPackage(pkg *types.Package) *Package {
 	if pkg == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(pkg)
-	cached, _ := v.(*Package)
-	if cached != nil {
-		return cached
-	}
-
-	var np Package
-	cache.Store(pkg, &np)
+	return load(u, pkg, func(np *Package) {
+		np.TypesPackage = pkg
+		np.Name = pkg.Name()
+		np.Path = pkg.Path()
+		np.u = u
+	})
+}
 
-	np.TypesPackage = pkg
-	np.Name = pkg.Name()
-	np.Path = pkg.Path()
-	np.Imports = make([]*Package, len(pkg.Imports()))
-	np.Funcs = map[string]*Func{}
-	np.Vars = map[string]*Var{}
-	np.Consts = map[string]*Const{}
-	np.Types = map[string]*TypeName{}
+// Imports returns imported packages.
+func (pkg *Package) Imports() []*Package {
+	pkg.importsOnce.Do(func() {
+		imports := pkg.TypesPackage.Imports()
+		pkg.imports = make([]*Package, len(imports))
+		for i, p := range imports {
+			pkg.imports[i] = pkg.u.Package(p)
+		}
+	})
+	return pkg.imports
+}
 
-	for i, p := range pkg.Imports() {
-		np.Imports[i] = NewPackage(p)
-	}
+func (pkg *Package) loadObjects() *packageObjects {
+	pkg.objectsOnce.Do(func() {
+		objs := packageObjects{
+			funcs:  map[string]*Func{},
+			vars:   map[string]*Var{},
+			consts: map[string]*Const{},
+			types:  map[string]*TypeName{},
+		}
 
-	for _, n := range pkg.Scope().Names() {
-		obj := pkg.Scope().Lookup(n)
-		switch obj := obj.(type) {
-		case *types.Func:
-			np.Funcs[n] = NewFunc(obj)
-			np.FuncNames = append(np.FuncNames, n)
-		case *types.Var:
-			np.Vars[n] = NewVar(obj)
-			np.VarNames = append(np.VarNames, n)
-		case *types.Const:
-			np.Consts[n] = NewConst(obj)
-			np.ConstNames = append(np.ConstNames, n)
-		case *types.TypeName:
-			np.Types[n] = NewTypeName(obj)
-			np.TypeNames = append(np.TypeNames, n)
+		scope := pkg.TypesPackage.Scope()
+		for _, n := range scope.Names() {
+			switch obj := scope.Lookup(n).(type) {
+			case *types.Func:
+				objs.funcs[n] = pkg.u.Func(obj)
+				objs.funcNames = append(objs.funcNames, n)
+			case *types.Var:
+				objs.vars[n] = pkg.u.Var(obj)
+				objs.varNames = append(objs.varNames, n)
+			case *types.Const:
+				objs.consts[n] = pkg.u.Const(obj)
+				objs.constNames = append(objs.constNames, n)
+			case *types.TypeName:
+				objs.types[n] = pkg.u.TypeName(obj)
+				objs.typeNames = append(objs.typeNames, n)
+			}
 		}
-	}
 
-	return &np
+		pkg.objects = objs
+	})
+	return &pkg.objects
+}
 
+// Funcs returns package-level functions by their names.
+func (pkg *Package) Funcs() map[string]*Func {
+	return pkg.loadObjects().funcs
+}
+
+// FuncNames returns sorted names of package-level functions.
+func (pkg *Package) FuncNames() []string {
+	return pkg.loadObjects().funcNames
+}
+
+// Vars returns package-level variables by their names.
+func (pkg *Package) Vars() map[string]*Var {
+	return pkg.loadObjects().vars
+}
+
+// VarNames returns sorted names of package-level variables.
+func (pkg *Package) VarNames() []string {
+	return pkg.loadObjects().varNames
+}
+
+// Consts returns package-level constants by their names.
+func (pkg *Package) Consts() map[string]*Const {
+	return pkg.loadObjects().consts
+}
+
+// ConstNames returns sorted names of package-level constants.
+func (pkg *Package) ConstNames() []string {
+	return pkg.loadObjects().constNames
+}
+
+// Types returns package-level type names by their names.
+func (pkg *Package) Types() map[string]*TypeName {
+	return pkg.loadObjects().types
+}
+
+// TypeNames returns sorted names of package-level type names.
+func (pkg *Package) TypeNames() []string {
+	return pkg.loadObjects().typeNames
+}
+
+// SetPackageInfo sets information of pkg which [types.Package] does not have
+// such as errors and test variants to the knife package.
+func SetPackageInfo(kpkg *Package, pkg *packages.Package) {
+	if kpkg == nil || pkg == nil {
+		return
+	}
+	kpkg.Errors = PackageErrors(pkg)
+	kpkg.IllTyped = pkg.IllTyped
+	kpkg.ForTest = pkg.ForTest
+	kpkg.IsTestVariant = pkg.ForTest != ""
+	if pkg.Module != nil {
+		kpkg.Module = pkg.Module.Path
+	}
 }
 
 func (pkg *Package) Objects() iter.Seq2[string, Object] {
 	return func(yield func(string, Object) bool) {
-		for name, f := range pkg.Funcs {
+		for name, f := range pkg.Funcs() {
 			if !yield(name, f) {
 				return
 			}
 		}
 
-		for name, v := range pkg.Vars {
+		for name, v := range pkg.Vars() {
 			if !yield(name, v) {
 				return
 			}
 		}
 
-		for name, c := range pkg.Consts {
+		for name, c := range pkg.Consts() {
 			if !yield(name, c) {
 				return
 			}
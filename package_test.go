@@ -0,0 +1,47 @@
+package knife
+
+import (
+	"go/types"
+	"slices"
+	"testing"
+)
+
+func TestPackage_Lazy(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "fmt")
+
+	u := NewUniverse()
+	pkg := u.Package(k.Packages()[0].Types)
+	if pkg.Name != "fmt" {
+		t.Errorf("Name = %q, want fmt", pkg.Name)
+	}
+
+	if n := len(u.objects); n != 1 {
+		t.Errorf("creating a package must not create other objects: %d objects are created", n)
+	}
+
+	if !slices.Contains(pkg.FuncNames(), "Println") || pkg.Funcs()["Println"] == nil {
+		t.Error("Println is not found")
+	}
+
+	if len(u.objects) == 1 {
+		t.Error("Funcs must create objects on first access")
+	}
+
+	stringer := pkg.Types()["Stringer"].Type
+	if got := stringer.Named().MethodNames(); len(got) != 0 {
+		t.Errorf("Stringer.MethodNames() = %v, want empty", got)
+	}
+
+	if got := stringer.Interface().MethodNames(); !slices.Equal(got, []string{"String"}) {
+		t.Errorf("Stringer.Interface().MethodNames() = %v, want [String]", got)
+	}
+
+	scope := u.Scope(pkg.TypesPackage.Scope())
+	if scope.Parent() != u.Scope(types.Universe) {
+		t.Error("parent of a package scope must be the universe scope")
+	}
+
+	if scope.Objects()["Println"] != pkg.Funcs()["Println"] {
+		t.Error("a scope must refer the same objects as the package")
+	}
+}
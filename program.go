@@ -0,0 +1,177 @@
+package knife
+
+import (
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gostaticanalysis/analysisutil"
+)
+
+// Program is a set of packages which is given to a template at once.
+// Types and functions of the packages are indexed by their qualified names
+// such as "io.Reader" and created on first access.
+type Program struct {
+	Packages []*Package
+
+	u         *Universe
+	once      sync.Once
+	funcs     map[string]*Func
+	types     map[string]*TypeName
+	funcNames []string
+	typeNames []string
+}
+
+// NewProgram creates a [Program] of the packages.
+// Objects which are looked up by [Program.Lookup] are created
+// in the universe of the packages.
+func NewProgram(pkgs []*Package) *Program {
+	u := NewUniverse()
+	if len(pkgs) > 0 && pkgs[0].u != nil {
+		u = pkgs[0].u
+	}
+	return &Program{Packages: pkgs, u: u}
+}
+
+func (p *Program) load() {
+	p.once.Do(func() {
+		p.funcs = make(map[string]*Func)
+		p.types = make(map[string]*TypeName)
+		for _, pkg := range p.Packages {
+			for name, f := range pkg.Funcs() {
+				p.funcs[pkg.Path+"."+name] = f
+			}
+			for name, t := range pkg.Types() {
+				p.types[pkg.Path+"."+name] = t
+			}
+		}
+		p.funcNames = slices.Sorted(maps.Keys(p.funcs))
+		p.typeNames = slices.Sorted(maps.Keys(p.types))
+	})
+}
+
+// Funcs returns package-level functions of all packages by their qualified names.
+func (p *Program) Funcs() map[string]*Func {
+	p.load()
+	return p.funcs
+}
+
+// FuncNames returns sorted qualified names of the functions.
+func (p *Program) FuncNames() []string {
+	p.load()
+	return p.funcNames
+}
+
+// Types returns package-level type names of all packages by their qualified names.
+func (p *Program) Types() map[string]*TypeName {
+	p.load()
+	return p.types
+}
+
+// TypeNames returns sorted qualified names of the type names.
+func (p *Program) TypeNames() []string {
+	p.load()
+	return p.typeNames
+}
+
+// Package returns a package which has the path.
+// It returns nil if the program does not have the package.
+func (p *Program) Package(path string) *Package {
+	for _, pkg := range p.Packages {
+		if pkg.Path == path {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// Lookup returns an object which is specified by a qualified name such as "io.Reader".
+// A field or a method is specified by a name such as "net/http.Request.URL".
+// The object is looked up from the packages of the program and their imports,
+// and then from all their dependencies.
+// A name without a package path is looked up from the universe scope.
+func (p *Program) Lookup(name string) Object {
+	if !strings.Contains(name, ".") {
+		return p.u.Object(types.Universe.Lookup(name))
+	}
+
+	// a package path may have dots such as "gopkg.in/yaml.v3"
+	for i := strings.LastIndex(name, "/") + 1; i < len(name); i++ {
+		if name[i] != '.' {
+			continue
+		}
+
+		objName, member, hasMember := strings.Cut(name[i+1:], ".")
+		obj := p.lookup(name[:i], objName)
+		if obj == nil {
+			continue
+		}
+
+		if !hasMember {
+			return p.u.Object(obj)
+		}
+		return p.lookupMember(obj, member)
+	}
+
+	return nil
+}
+
+func (p *Program) lookupMember(obj types.Object, name string) Object {
+	tn, _ := obj.(*types.TypeName)
+	if tn == nil {
+		return nil
+	}
+
+	switch m, index, _ := types.LookupFieldOrMethod(tn.Type(), true, tn.Pkg(), name); m := m.(type) {
+	case *types.Func:
+		return p.u.Func(m)
+	case *types.Var:
+		if f := p.field(tn.Type(), index); f != nil {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// field returns a field which is selected by the index path from t.
+func (p *Program) field(t types.Type, index []int) *Field {
+	for i, idx := range index {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+
+		s, _ := t.Underlying().(*types.Struct)
+		if s == nil {
+			return nil
+		}
+
+		f := s.Field(idx)
+		if i == len(index)-1 {
+			return p.u.Field(p.u.Struct(s), f, s.Tag(idx))
+		}
+		t = f.Type()
+	}
+	return nil
+}
+
+func (p *Program) lookup(path, name string) types.Object {
+	var pkgs []*types.Package
+	for _, pkg := range p.Packages {
+		pkgs = append(pkgs, pkg.TypesPackage)
+		pkgs = append(pkgs, pkg.TypesPackage.Imports()...)
+	}
+
+	if obj := analysisutil.LookupFromImports(pkgs, path, name); obj != nil {
+		return obj
+	}
+
+	pkgs = pkgs[:0]
+	for _, pkg := range withDeps(p.Packages) {
+		pkgs = append(pkgs, pkg.TypesPackage)
+	}
+
+	return analysisutil.LookupFromImports(pkgs, path, name)
+}
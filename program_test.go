@@ -0,0 +1,50 @@
+package knife
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKnife_ExecuteAll(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/program/...")
+
+	cases := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "packages",
+			template: `{{range .Packages}}{{.Name}} {{end}}`,
+			want:     "a b ",
+		},
+		{
+			name:     "types",
+			template: `{{range .TypeNames}}{{.}} {{end}}`,
+			want:     "github.com/gostaticanalysis/knife/testdata/program/a.File github.com/gostaticanalysis/knife/testdata/program/b.Buffer github.com/gostaticanalysis/knife/testdata/program/b.Config ",
+		},
+		{
+			name:     "funcs",
+			template: `{{(index .Funcs "github.com/gostaticanalysis/knife/testdata/program/a.Open").Name}}`,
+			want:     "Open",
+		},
+		{
+			name:     "implements",
+			template: `{{$r := typeof "io.Reader"}}{{range .Types}}{{if implements . $r}}{{.Name}} {{end}}{{end}}`,
+			want:     "File Buffer ",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := k.ExecuteAll(&buf, tt.template, nil); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+)
+
+// RefKind is a kind of a reference.
+type RefKind string
+
+const (
+	// RefRead is a reference which reads the object.
+	RefRead RefKind = "read"
+	// RefWrite is a reference which assigns to the object.
+	RefWrite RefKind = "write"
+	// RefCall is a reference which calls the object.
+	RefCall RefKind = "call"
+	// RefEmbed is a reference which embeds the type into a struct or an interface.
+	RefEmbed RefKind = "embed"
+)
+
+// Ref is a reference to an object.
+type Ref struct {
+	// Object is the referred object.
+	Object Object
+	Kind   RefKind
+	// Implicit reports whether the object is referred implicitly
+	// such as an embedded field through which a promoted field is selected.
+	Implicit bool
+	// Func is the function which encloses the reference.
+	// It is nil if the reference is not in a function.
+	Func    *Func
+	Package *Package
+	pos     token.Pos
+}
+
+var _ fmt.Stringer = (*Ref)(nil)
+
+func (r *Ref) Pos() token.Pos {
+	return r.pos
+}
+
+func (r *Ref) String() string {
+	return fmt.Sprintf("%s %s", r.Kind, r.Object)
+}
+
+// Refs returns references to the object in pkgs in the universe.
+// obj is an [Object] or a [types.Object].
+// The packages must have syntax and type information.
+// The references are sorted by their positions.
+func (u *Universe) Refs(pkgs []*packages.Package, obj any) []*Ref {
+	var target types.Object
+	switch obj := obj.(type) {
+	case Object:
+		if obj != nil {
+			target = obj.TypesObject()
+		}
+	case types.Object:
+		target = obj
+	}
+
+	if target == nil || len(pkgs) == 0 {
+		return nil
+	}
+
+	fset := pkgs[0].Fset
+	m := &refMatcher{fset: fset, target: target, key: objectKey(fset, target)}
+	kobj, ok := obj.(Object)
+	if !ok {
+		kobj = u.Object(target)
+	}
+
+	var refs []*Ref
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+
+		info := pkg.TypesInfo
+		kpkg := u.Package(pkg.Types)
+		newRef := func(kind RefKind, implicit bool, pos token.Pos, stack []ast.Node) *Ref {
+			return &Ref{
+				Object:   kobj,
+				Kind:     kind,
+				Implicit: implicit,
+				Func:     enclosingFunc(u, info, stack),
+				Package:  kpkg,
+				pos:      pos,
+			}
+		}
+
+		nodeTypes := []ast.Node{(*ast.Ident)(nil), (*ast.SelectorExpr)(nil)}
+		inspector.New(pkg.Syntax).WithStack(nodeTypes, func(n ast.Node, push bool, stack []ast.Node) bool {
+			if !push {
+				return true
+			}
+
+			switch n := n.(type) {
+			case *ast.Ident:
+				if m.match(info.Uses[n]) {
+					refs = append(refs, newRef(refKind(info, n, stack), false, n.Pos(), stack))
+				}
+			case *ast.SelectorExpr:
+				// embedded fields which are selected implicitly
+				sel := info.Selections[n]
+				if sel == nil || len(sel.Index()) < 2 {
+					break
+				}
+				for _, f := range embeddedFields(sel) {
+					if m.match(f) {
+						refs = append(refs, newRef(RefRead, true, n.Sel.Pos(), stack))
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	slices.SortStableFunc(refs, func(a, b *Ref) int {
+		pa, pb := fset.Position(a.pos), fset.Position(b.pos)
+		return cmp.Or(
+			cmp.Compare(pa.Filename, pb.Filename),
+			cmp.Compare(pa.Offset, pb.Offset),
+		)
+	})
+
+	// a file may be loaded as several test variants
+	return slices.CompactFunc(refs, func(a, b *Ref) bool {
+		return a.Kind == b.Kind && a.Implicit == b.Implicit &&
+			fset.Position(a.pos) == fset.Position(b.pos)
+	})
+}
+
+type refMatcher struct {
+	fset   *token.FileSet
+	target types.Object
+	key    refKey
+}
+
+type refKey struct {
+	path string
+	name string
+	pos  token.Position
+}
+
+func objectKey(fset *token.FileSet, obj types.Object) refKey {
+	obj = originObject(obj)
+	var path string
+	if obj.Pkg() != nil {
+		path = obj.Pkg().Path()
+	}
+	return refKey{path: path, name: obj.Name(), pos: fset.Position(obj.Pos())}
+}
+
+// match reports whether obj is the target.
+// Objects which are loaded in different test variants of a package
+// are compared by their package paths, names and positions.
+func (m *refMatcher) match(obj types.Object) bool {
+	if obj == nil || obj.Name() != m.target.Name() {
+		return false
+	}
+
+	obj = originObject(obj)
+	if obj == originObject(m.target) {
+		return true
+	}
+
+	if !obj.Pos().IsValid() || obj.Pkg() == nil {
+		return false
+	}
+
+	return objectKey(m.fset, obj) == m.key
+}
+
+func originObject(obj types.Object) types.Object {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin()
+	case *types.Var:
+		return obj.Origin()
+	}
+	return obj
+}
+
+// refKind returns a kind of the reference by the identifier.
+// The last element of the stack is the identifier.
+func refKind(info *types.Info, id *ast.Ident, stack []ast.Node) RefKind {
+	var node ast.Node = id
+	i := len(stack) - 2
+	if i >= 0 {
+		if sel, ok := stack[i].(*ast.SelectorExpr); ok && sel.Sel == id {
+			node = sel
+			i--
+		}
+	}
+
+	for ; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		node = stack[i]
+	}
+
+	if i < 0 {
+		return RefRead
+	}
+
+	switch parent := stack[i].(type) {
+	case *ast.CallExpr:
+		_, isType := info.Uses[id].(*types.TypeName)
+		if parent.Fun == node && !isType {
+			return RefCall
+		}
+	case *ast.AssignStmt:
+		if slices.Contains(parent.Lhs, node.(ast.Expr)) {
+			return RefWrite
+		}
+	case *ast.IncDecStmt:
+		return RefWrite
+	case *ast.RangeStmt:
+		if parent.Key == node || parent.Value == node {
+			return RefWrite
+		}
+	case *ast.KeyValueExpr:
+		// a key of a struct literal
+		if v, ok := info.Uses[id].(*types.Var); ok && v.IsField() && parent.Key == node {
+			return RefWrite
+		}
+	case *ast.StarExpr, *ast.Field:
+		if isEmbedded(stack[:i+1]) {
+			return RefEmbed
+		}
+	}
+
+	return RefRead
+}
+
+// isEmbedded reports whether the last element of the stack is
+// an embedded field of a struct or an interface.
+func isEmbedded(stack []ast.Node) bool {
+	i := len(stack) - 1
+	if _, ok := stack[i].(*ast.StarExpr); ok {
+		i--
+	}
+
+	if i < 2 {
+		return false
+	}
+
+	field, ok := stack[i].(*ast.Field)
+	if !ok || len(field.Names) != 0 {
+		return false
+	}
+
+	switch stack[i-2].(type) {
+	case *ast.StructType, *ast.InterfaceType:
+		return true
+	}
+	return false
+}
+
+// embeddedFields returns embedded fields which are selected implicitly by the selection.
+func embeddedFields(sel *types.Selection) []*types.Var {
+	var fields []*types.Var
+	t := sel.Recv()
+	index := sel.Index()
+	for _, idx := range index[:len(index)-1] {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+
+		s, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+
+		f := s.Field(idx)
+		fields = append(fields, f)
+		t = f.Type()
+	}
+	return fields
+}
+
+func enclosingFunc(u *Universe, info *types.Info, stack []ast.Node) *Func {
+	for i := len(stack) - 1; i >= 0; i-- {
+		decl, ok := stack[i].(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		if f, ok := info.Defs[decl.Name].(*types.Func); ok {
+			return u.Func(f)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package knife
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestKnife_Refs(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/refs")
+
+	const pkg = "github.com/gostaticanalysis/knife/testdata/refs"
+	cases := []struct {
+		name string
+		want []string
+	}{
+		{
+			name: pkg + ".Config.Timeout",
+			want: []string{
+				"12:40 write (*Config).SetTimeout",
+				"15:30 write NewServer",
+				"16:4 write NewServer",
+				"17:17 read NewServer",
+				"21:31 read -",
+			},
+		},
+		{
+			name: pkg + ".Server.Config",
+			want: []string{
+				"15:15 write NewServer",
+				"16:4 read(implicit) NewServer",
+				"17:4 read(implicit) NewServer",
+				"17:17 read(implicit) NewServer",
+			},
+		},
+		{
+			name: pkg + ".Config",
+			want: []string{
+				"8:2 embed -",
+				"12:10 read (*Config).SetTimeout",
+				"15:23 read NewServer",
+				"21:22 read -",
+			},
+		},
+		{
+			name: pkg + ".Config.SetTimeout",
+			want: []string{
+				"17:4 call NewServer",
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := k.Program().Lookup(tt.name)
+			if obj == nil {
+				t.Fatalf("%s is not found", tt.name)
+			}
+
+			var got []string
+			for _, ref := range k.Refs(obj) {
+				got = append(got, refString(k, ref))
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func refString(k *Knife, ref *Ref) string {
+	pos := k.Position(ref)
+	kind := string(ref.Kind)
+	if ref.Implicit {
+		kind += "(implicit)"
+	}
+
+	fn := "-"
+	if ref.Func != nil {
+		fn = strings.ReplaceAll(ref.Func.TypesFunc.FullName(), ref.Package.Path+".", "")
+	}
+
+	return fmt.Sprintf("%d:%d %s %s", pos.Line, pos.Column, kind, fn)
+}
@@ -0,0 +1,291 @@
+package knife
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ReportFormat is an output format of diagnostics.
+type ReportFormat int
+
+const (
+	// ReportPlain prints a diagnostic per line as "file:line:col: message".
+	ReportPlain ReportFormat = iota
+	// ReportJSON prints diagnostics as a JSON array.
+	ReportJSON
+	// ReportSARIF prints diagnostics as a SARIF 2.1.0 log.
+	ReportSARIF
+)
+
+var _ flag.Value = (*ReportFormat)(nil)
+
+func (f *ReportFormat) String() string {
+	if f == nil {
+		return ""
+	}
+
+	switch *f {
+	case ReportPlain:
+		return "plain"
+	case ReportJSON:
+		return "json"
+	case ReportSARIF:
+		return "sarif"
+	}
+	return fmt.Sprintf("ReportFormat(%d)", int(*f))
+}
+
+// Set implements [flag.Value].
+func (f *ReportFormat) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "plain":
+		*f = ReportPlain
+	case "json":
+		*f = ReportJSON
+	case "sarif":
+		*f = ReportSARIF
+	default:
+		return fmt.Errorf("unknown report format %q: expected plain, json or sarif", s)
+	}
+	return nil
+}
+
+// Severity is a severity of a [Diagnostic].
+type Severity int
+
+const (
+	// SeverityError is a finding which must be fixed.
+	SeverityError Severity = iota
+	// SeverityWarning is a finding which should be fixed.
+	SeverityWarning
+	// SeverityInfo is an informative finding.
+	SeverityInfo
+)
+
+var _ flag.Value = (*Severity)(nil)
+
+func (s *Severity) String() string {
+	if s == nil {
+		return ""
+	}
+
+	switch *s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return fmt.Sprintf("Severity(%d)", int(*s))
+}
+
+// Set implements [flag.Value].
+// "note" is accepted as "info" because SARIF calls it so.
+func (s *Severity) Set(v string) error {
+	switch strings.ToLower(v) {
+	case "error":
+		*s = SeverityError
+	case "warning", "warn":
+		*s = SeverityWarning
+	case "info", "note":
+		*s = SeverityInfo
+	default:
+		return fmt.Errorf("unknown severity %q: expected error, warning or info", v)
+	}
+	return nil
+}
+
+// sarifLevel returns a level of a SARIF result.
+func (s Severity) sarifLevel() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+	return "error"
+}
+
+// Diagnostic is a finding which is reported at a position.
+type Diagnostic struct {
+	Pos token.Position
+	// Rule is an ID of the rule which reports the diagnostic such as "layers".
+	Rule     string
+	Message  string
+	Severity Severity
+}
+
+var _ fmt.Stringer = (*Diagnostic)(nil)
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+// WriteDiagnostics writes the diagnostics in the format.
+// tool is a name of the tool which is used in SARIF.
+func WriteDiagnostics(w io.Writer, tool string, diags []*Diagnostic, format ReportFormat) error {
+	switch format {
+	case ReportPlain:
+		for _, d := range diags {
+			if _, err := fmt.Fprintln(w, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ReportJSON:
+		return writeJSON(w, jsonDiagnostics(diags))
+	case ReportSARIF:
+		return writeJSON(w, sarifLogOf(tool, diags))
+	}
+	return fmt.Errorf("unsupported report format: %s", &format)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+type jsonDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func jsonDiagnostics(diags []*Diagnostic) []*jsonDiagnostic {
+	jds := make([]*jsonDiagnostic, len(diags))
+	for i, d := range diags {
+		jds[i] = &jsonDiagnostic{
+			File:     d.Pos.Filename,
+			Line:     d.Pos.Line,
+			Column:   d.Pos.Column,
+			Rule:     d.Rule,
+			Severity: d.Severity.String(),
+			Message:  d.Message,
+		}
+	}
+	return jds
+}
+
+// SARIF 2.1.0 log which has only properties knife uses.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type sarifLog struct {
+	Version string      `json:"version"`
+	Schema  string      `json:"$schema"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func sarifLogOf(tool string, diags []*Diagnostic) *sarifLog {
+	run := &sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           tool,
+			InformationURI: "https://github.com/gostaticanalysis/knife",
+			Rules:          []*sarifRule{},
+		}},
+		Results: []*sarifResult{},
+	}
+
+	var ruleIDs []string
+	for _, d := range diags {
+		if !slices.Contains(ruleIDs, d.Rule) {
+			ruleIDs = append(ruleIDs, d.Rule)
+		}
+
+		result := &sarifResult{
+			RuleID:  d.Rule,
+			Level:   d.Severity.sarifLevel(),
+			Message: sarifMessage{Text: d.Message},
+		}
+
+		if d.Pos.IsValid() {
+			result.Locations = []*sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(d.Pos.Filename)},
+					Region:           sarifRegion{StartLine: d.Pos.Line, StartColumn: d.Pos.Column},
+				},
+			}}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	slices.Sort(ruleIDs)
+	for _, id := range ruleIDs {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: id})
+	}
+
+	return &sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []*sarifRun{run},
+	}
+}
+
+// sarifURI returns a URI of the file which is relative to the current directory if possible.
+func sarifURI(filename string) string {
+	if wd, err := os.Getwd(); err == nil && filepath.IsAbs(filename) {
+		if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+	}
+	return filepath.ToSlash(filename)
+}
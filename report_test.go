@@ -0,0 +1,199 @@
+package knife
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteDiagnostics(t *testing.T) {
+	diags := []*Diagnostic{
+		{Pos: token.Position{Filename: "a.go", Line: 3, Column: 8}, Rule: "layers", Message: "a must not import b"},
+	}
+
+	var plain strings.Builder
+	if err := WriteDiagnostics(&plain, "knife", diags, ReportPlain); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got, want := plain.String(), "a.go:3:8: a must not import b\n"; got != want {
+		t.Errorf("plain: got %q, want %q", got, want)
+	}
+
+	var jsonOut strings.Builder
+	if err := WriteDiagnostics(&jsonOut, "knife", diags, ReportJSON); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal([]byte(jsonOut.String()), &got); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(got) != 1 || got[0]["file"] != "a.go" || got[0]["line"] != 3.0 || got[0]["rule"] != "layers" {
+		t.Errorf("json: unexpected output %s", jsonOut.String())
+	}
+
+	var sarif strings.Builder
+	if err := WriteDiagnostics(&sarif, "knife", diags, ReportSARIF); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	if err := json.Unmarshal([]byte(sarif.String()), &log); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("sarif: unexpected output %s", sarif.String())
+	}
+
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "knife" || len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "layers" {
+		t.Errorf("sarif: unexpected tool %+v", run.Tool)
+	}
+
+	if len(run.Results) != 1 || run.Results[0].RuleID != "layers" ||
+		run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI != "a.go" ||
+		run.Results[0].Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("sarif: unexpected results %+v", run.Results)
+	}
+}
+
+func TestWriteDiagnostics_Severity(t *testing.T) {
+	diags := []*Diagnostic{
+		{Pos: token.Position{Filename: "a.go", Line: 1, Column: 1}, Rule: "r", Message: "e"},
+		{Pos: token.Position{Filename: "a.go", Line: 2, Column: 1}, Rule: "r", Message: "w", Severity: SeverityWarning},
+		{Pos: token.Position{Filename: "a.go", Line: 3, Column: 1}, Rule: "r", Message: "i", Severity: SeverityInfo},
+	}
+
+	var jsonOut strings.Builder
+	if err := WriteDiagnostics(&jsonOut, "knife", diags, ReportJSON); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var jds []struct {
+		Severity string `json:"severity"`
+	}
+	if err := json.Unmarshal([]byte(jsonOut.String()), &jds); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var sarif strings.Builder
+	if err := WriteDiagnostics(&sarif, "knife", diags, ReportSARIF); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				Level string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(sarif.String()), &log); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	wantSeverities := []string{"error", "warning", "info"}
+	wantLevels := []string{"error", "warning", "note"}
+	for i := range diags {
+		if jds[i].Severity != wantSeverities[i] {
+			t.Errorf("json: got %q, want %q", jds[i].Severity, wantSeverities[i])
+		}
+
+		if got := log.Runs[0].Results[i].Level; got != wantLevels[i] {
+			t.Errorf("sarif: got %q, want %q", got, wantLevels[i])
+		}
+	}
+}
+
+func TestTemplate_Diag(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/json/a")
+
+	cases := []struct {
+		name    string
+		tmpl    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "default severity",
+			tmpl: `{{range .Funcs}}{{diag . (printf "func %s" .Name)}}{{end}}`,
+			want: []string{"a.go:22:6: func Copy (error)"},
+		},
+		{
+			name: "report",
+			tmpl: `{{report .Types.Node "node" "warning"}}`,
+			want: []string{"a.go:6:6: node (warning)"},
+		},
+		{
+			name:    "unknown severity",
+			tmpl:    `{{diag .Types.Node "node" "fatal"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "no position",
+			tmpl:    `{{diag .Name "node"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			opt := &ExecuteOption{
+				Report: func(d *Diagnostic) {
+					got = append(got, fmt.Sprintf("%s:%d:%d: %s (%s)", filepath.Base(d.Pos.Filename), d.Pos.Line, d.Pos.Column, d.Message, &d.Severity))
+				},
+			}
+
+			var buf strings.Builder
+			err := k.Execute(&buf, k.Packages()[0], tt.tmpl, opt)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expected error")
+			case !tt.wantErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			if buf.String() != "" {
+				t.Errorf("unexpected output %q", buf.String())
+			}
+		})
+	}
+}
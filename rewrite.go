@@ -0,0 +1,289 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Edit is an edit of a source file which replaces the range [Pos, End) with NewText.
+// An insertion has same Pos and End and a deletion has empty NewText.
+type Edit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText string
+}
+
+var _ fmt.Stringer = (*Edit)(nil)
+
+func (e *Edit) String() string {
+	switch {
+	case e.Pos == e.End:
+		return fmt.Sprintf("insert %q", e.NewText)
+	case e.NewText == "":
+		return "delete"
+	}
+	return fmt.Sprintf("replace with %q", e.NewText)
+}
+
+// editRange returns a range of v which is edited by replace, insertBefore and delete.
+// v has Pos and End methods such as [*ASTNode] or it is an object
+// whose range is its name.
+func editRange(v any) (pos, end token.Pos, _ error) {
+	switch v := v.(type) {
+	case *ASTNode:
+		return v.Node.Pos(), v.Node.End(), nil
+	case interface{ End() token.Pos }:
+		if n, ok := v.(interface{ Pos() token.Pos }); ok {
+			return n.Pos(), v.End(), nil
+		}
+	case Object:
+		o := v.TypesObject()
+		return o.Pos(), o.Pos() + token.Pos(len(o.Name())), nil
+	}
+	return token.NoPos, token.NoPos, fmt.Errorf("%T does not have a range", v)
+}
+
+// ApplyEdits applies the edits to the files and formats them with gofmt.
+// It returns new contents of the edited files by their names.
+// The files are read by [ReadSource] with the overlay which is used to load the packages,
+// because positions of the edits are offsets in the loaded contents.
+// Duplicated edits are applied once because a file may belong to several packages
+// such as test variants. Overlapping edits are rejected.
+func ApplyEdits(fset *token.FileSet, overlay map[string][]byte, edits []*Edit) (map[string][]byte, error) {
+	type offsetEdit struct {
+		start, end int
+		text       string
+	}
+
+	byFile := make(map[string][]offsetEdit)
+	for _, e := range edits {
+		f := fset.File(e.Pos)
+		if f == nil || (e.End.IsValid() && fset.File(e.End) != f) || e.End < e.Pos {
+			return nil, fmt.Errorf("invalid range of edit: %s", e)
+		}
+		byFile[f.Name()] = append(byFile[f.Name()], offsetEdit{
+			start: f.Offset(e.Pos),
+			end:   f.Offset(e.End),
+			text:  e.NewText,
+		})
+	}
+
+	files := make(map[string][]byte, len(byFile))
+	for name, es := range byFile {
+		slices.SortFunc(es, func(a, b offsetEdit) int {
+			return cmp.Or(
+				cmp.Compare(a.start, b.start),
+				cmp.Compare(a.end, b.end),
+				cmp.Compare(a.text, b.text),
+			)
+		})
+		es = slices.Compact(es)
+
+		for i := 1; i < len(es); i++ {
+			prev, e := es[i-1], es[i]
+			// two insertions at a same position have no order
+			if prev.end > e.start || (prev.start == prev.end && e.start == e.end && prev.start == e.start) {
+				return nil, fmt.Errorf("%s: overlapping edits at offsets %d and %d", name, prev.start, e.start)
+			}
+		}
+
+		src, err := ReadSource(name, overlay)
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		var last int
+		for _, e := range es {
+			if e.end > len(src) {
+				return nil, fmt.Errorf("%s: edit is out of the file", name)
+			}
+			b.WriteString(string(src[last:e.start]))
+			b.WriteString(e.text)
+			last = e.end
+		}
+		b.WriteString(string(src[last:]))
+
+		formatted, err := format.Source([]byte(b.String()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot format %s after edits: %w", name, err)
+		}
+		files[name] = formatted
+	}
+
+	return files, nil
+}
+
+// ReadSource reads the file from the overlay if it has the file,
+// otherwise it reads the file from the disk like the go command.
+func ReadSource(name string, overlay map[string][]byte) ([]byte, error) {
+	if src, ok := overlay[name]; ok {
+		return src, nil
+	}
+
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", name, err)
+	}
+	return src, nil
+}
+
+// UnifiedDiff returns a unified diff between old and new contents of the file.
+// It returns an empty string if they are same.
+func UnifiedDiff(name string, old, new []byte) string {
+	a, b := splitLines(string(old)), splitLines(string(new))
+	ops := diffLines(a, b)
+
+	const context = 3
+	var hunks strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// a hunk has changes which are closer than 2*context lines
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = next
+		}
+
+		var oldLines, newLines int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldLines++
+			}
+			if op.kind != '-' {
+				newLines++
+			}
+		}
+		fmt.Fprintf(&hunks, "@@ -%s +%s @@\n",
+			hunkRange(ops[start].oldLine, oldLines), hunkRange(ops[start].newLine, newLines))
+		for _, op := range ops[start:end] {
+			hunks.WriteString(string(op.kind) + op.text)
+			if !strings.HasSuffix(op.text, "\n") {
+				hunks.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+
+	if hunks.Len() == 0 {
+		return ""
+	}
+	return "--- " + name + "\n+++ " + name + "\n" + hunks.String()
+}
+
+func hunkRange(line, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, n)
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffOp is a line of a diff.
+// kind is ' ' for a common line, '-' for a deleted line and '+' for an inserted line.
+// oldLine and newLine are 1-based line numbers of the line in old and new.
+type diffOp struct {
+	kind             byte
+	text             string
+	oldLine, newLine int
+}
+
+// diffLines returns a line diff of a and b with the longest common subsequence.
+// Common prefix and suffix are trimmed first, so small edits of large files are cheap.
+func diffLines(a, b []string) []diffOp {
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is a length of the LCS of ma[i:] and mb[j:]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	oldLine, newLine := 1, 1
+	add := func(kind byte, text string) {
+		ops = append(ops, diffOp{kind: kind, text: text, oldLine: oldLine, newLine: newLine})
+		if kind != '+' {
+			oldLine++
+		}
+		if kind != '-' {
+			newLine++
+		}
+	}
+
+	for _, line := range a[:prefix] {
+		add(' ', line)
+	}
+
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			add(' ', ma[i])
+			i++
+			j++
+		case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+			add('-', ma[i])
+			i++
+		default:
+			add('+', mb[j])
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		add(' ', line)
+	}
+
+	return ops
+}
@@ -0,0 +1,215 @@
+package knife
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyEdits(t *testing.T) {
+	const src = "package a\n\nfunc f() {\n\tprintln(1)\n\tprintln(2)\n}\n"
+
+	// offsets in src
+	call1 := strings.Index(src, "println(1)")
+	call2 := strings.Index(src, "println(2)")
+
+	cases := []struct {
+		name    string
+		edits   [][3]any // start offset, end offset and new text
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "replace",
+			edits: [][3]any{{call1, call1 + len("println(1)"), "print(1)"}},
+			want:  "package a\n\nfunc f() {\n\tprint(1)\n\tprintln(2)\n}\n",
+		},
+		{
+			name:  "insert and delete",
+			edits: [][3]any{{call1, call1, "println(0);"}, {call2, call2 + len("println(2)"), ""}},
+			want:  "package a\n\nfunc f() {\n\tprintln(0)\n\tprintln(1)\n\n}\n",
+		},
+		{
+			name: "duplicated",
+			edits: [][3]any{
+				{call1, call1 + len("println(1)"), "print(1)"},
+				{call1, call1 + len("println(1)"), "print(1)"},
+			},
+			want: "package a\n\nfunc f() {\n\tprint(1)\n\tprintln(2)\n}\n",
+		},
+		{
+			name: "overlapping",
+			edits: [][3]any{
+				{call1, call1 + len("println(1)"), "print(1)"},
+				{call1 + 1, call1 + 2, "x"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "insertions at a same position",
+			edits:   [][3]any{{call1, call1, "a()\n"}, {call1, call1, "b()\n"}},
+			wantErr: true,
+		},
+		{
+			name:    "broken syntax",
+			edits:   [][3]any{{call1, call1, "}"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "a.go")
+			if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, name, nil, 0)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			tf := fset.File(f.Pos())
+
+			edits := make([]*Edit, len(tt.edits))
+			for i, e := range tt.edits {
+				edits[i] = &Edit{Pos: tf.Pos(e[0].(int)), End: tf.Pos(e[1].(int)), NewText: e[2].(string)}
+			}
+
+			files, err := ApplyEdits(fset, nil, edits)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expected error")
+			case !tt.wantErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.wantErr:
+				return
+			}
+
+			if got := string(files[name]); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+
+	want := `--- x.go
++++ x.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -9,3 +9,4 @@
+ i
+ j
+ k
++l
+`
+	if got := UnifiedDiff("x.go", []byte(old), []byte(new)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := UnifiedDiff("x.go", []byte(old), []byte(old)); got != "" {
+		t.Errorf("unexpected diff %q", got)
+	}
+}
+
+func TestTemplate_Rewrite(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "./testdata/rewrite/a")
+
+	cases := []struct {
+		name    string
+		xpath   string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "replace xpath nodes",
+			xpath: `//*[@type="CallExpr"]/Fun[@type="SelectorExpr"]/Sel[@Name="Println"]`,
+			tmpl:  `{{range .}}{{replace . "Print"}}{{end}}`,
+			want:  "package a\n\nimport \"fmt\"\n\nfunc Hello() {\n\tfmt.Print(\"hello\")\n}\n\nfunc Bye() {\n\tfmt.Print(\"bye\")\n}\n",
+		},
+		{
+			name: "rename object and insert",
+			tmpl: `{{with .Funcs.Bye}}{{replace . "Goodbye"}}{{insertBefore . "Deprecated"}}{{end}}`,
+			want: "package a\n\nimport \"fmt\"\n\nfunc Hello() {\n\tfmt.Println(\"hello\")\n}\n\nfunc DeprecatedGoodbye() {\n\tfmt.Println(\"bye\")\n}\n",
+		},
+		{
+			name:    "no range",
+			tmpl:    `{{delete .Name}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var edits []*Edit
+			opt := &ExecuteOption{
+				XPath: tt.xpath,
+				Edit:  func(e *Edit) { edits = append(edits, e) },
+			}
+
+			pkg := k.Packages()[0]
+			err := k.Execute(&strings.Builder{}, pkg, tt.tmpl, opt)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expected error")
+			case !tt.wantErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.wantErr:
+				return
+			}
+
+			files, err := ApplyEdits(pkg.Fset, nil, edits)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if got := string(files[pkg.GoFiles[0]]); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEdits_Overlay(t *testing.T) {
+	name, err := filepath.Abs(filepath.Join("testdata", "rewrite", "a", "a.go"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// positions of the edits are shifted from the file on the disk
+	const src = "// Package a is edited in an editor.\npackage a\n\nfunc Bye() {}\n"
+	overlay := map[string][]byte{name: []byte(src)}
+
+	opt := &KnifeOption{BuildContext: BuildContext{Overlay: overlay}}
+	k := newTestKnife(t, opt, "./testdata/rewrite/a")
+
+	var edits []*Edit
+	execOpt := &ExecuteOption{Edit: func(e *Edit) { edits = append(edits, e) }}
+	pkg := k.Packages()[0]
+	if err := k.Execute(&strings.Builder{}, pkg, `{{replace .Funcs.Bye "Goodbye"}}`, execOpt); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	files, err := ApplyEdits(pkg.Fset, overlay, edits)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	want := "// Package a is edited in an editor.\npackage a\n\nfunc Goodbye() {}\n"
+	if got := string(files[name]); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -4,51 +4,92 @@ import (
 	"fmt"
 	"go/token"
 	"go/types"
+	"sync"
 )
 
+// Scope is a scope.
+// Parent, children and objects of the scope are created on first access.
 type Scope struct {
 	TypesScope *types.Scope
-	Parent     *Scope
-	Children   []*Scope
 	Pos        token.Pos
 	End        token.Pos
-	Objects    map[string]Object
-	Names      []string
+
+	u            *Universe
+	parentOnce   sync.Once
+	parent       *Scope
+	childrenOnce sync.Once
+	children     []*Scope
+	objectsOnce  sync.Once
+	objects      map[string]Object
+	names        []string
 }
 
 var _ fmt.Stringer = (*Scope)(nil)
 
+// NewScope creates a [Scope] in a new [Universe].
 func NewScope(s *types.Scope) *Scope {
+	return NewUniverse().Scope(s)
+}
+
+// Scope returns a [Scope] of s in the universe.
+func (u *Universe) Scope(s *types.Scope) *Scope {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newScope(s)
+}
+
+func (u *Universe) newScope(s *types.Scope) *Scope {
 	if s == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(s)
-	cached, _ := v.(*Scope)
-	if cached != nil {
-		return cached
-	}
+	return load(u, s, func(ns *Scope) {
+		ns.TypesScope = s
+		ns.Pos = s.Pos()
+		ns.End = s.End()
+		ns.u = u
+	})
+}
 
-	var ns Scope
-	cache.Store(s, &ns)
+// Parent returns the parent scope.
+func (s *Scope) Parent() *Scope {
+	s.parentOnce.Do(func() {
+		s.parent = s.u.Scope(s.TypesScope.Parent())
+	})
+	return s.parent
+}
 
-	ns.TypesScope = s
-	ns.Parent = NewScope(s.Parent())
-	ns.Children = make([]*Scope, s.NumChildren())
-	for i := range ns.Children {
-		ns.Children[i] = NewScope(s.Child(i))
-	}
-	ns.Pos = s.Pos()
-	ns.End = s.End()
-	ns.Objects = make(map[string]Object, s.Len())
-	ns.Names = make([]string, s.Len())
-	for i, name := range s.Names() {
-		ns.Names[i] = name
-		o := s.Lookup(name)
-		ns.Objects[name] = NewObject(o)
-	}
+// Children returns the child scopes.
+func (s *Scope) Children() []*Scope {
+	s.childrenOnce.Do(func() {
+		s.children = make([]*Scope, s.TypesScope.NumChildren())
+		for i := range s.children {
+			s.children[i] = s.u.Scope(s.TypesScope.Child(i))
+		}
+	})
+	return s.children
+}
+
+func (s *Scope) loadObjects() {
+	s.objectsOnce.Do(func() {
+		s.objects = make(map[string]Object, s.TypesScope.Len())
+		s.names = s.TypesScope.Names()
+		for _, name := range s.names {
+			s.objects[name] = s.u.Object(s.TypesScope.Lookup(name))
+		}
+	})
+}
+
+// Objects returns objects in the scope by their names.
+func (s *Scope) Objects() map[string]Object {
+	s.loadObjects()
+	return s.objects
+}
 
-	return &ns
+// Names returns sorted names of the objects in the scope.
+func (s *Scope) Names() []string {
+	s.loadObjects()
+	return s.names
 }
 
 func (s *Scope) String() string {
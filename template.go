@@ -16,6 +16,7 @@ import (
 
 	"github.com/gostaticanalysis/analysisutil"
 	"github.com/gostaticanalysis/comment"
+	"golang.org/x/tools/go/packages"
 )
 
 type TempalteData struct {
@@ -24,18 +25,39 @@ type TempalteData struct {
 	TypesInfo *types.Info
 	Pkg       *types.Package
 	Extra     map[string]any
+	// Universe is used to create knife objects in the template.
+	// If it is nil, a new universe is used.
+	Universe *Universe
+	// Program has all packages which are loaded with the package.
+	// Pkg is nil when a template is executed over all packages.
+	Program *Program
+	// Packages are all loaded packages which have syntax and type information.
+	// They are used to find references.
+	Packages []*packages.Package
+	// CallGraph is used by callers, callees and reaches.
+	CallGraph *CallGraph
+	// Report is called by diag and report with a diagnostic and its position in Fset.
+	// If it is nil, diag and report fail.
+	Report func(pos token.Pos, d *Diagnostic)
+	// Edit is called by replace, insertBefore and delete with an edit.
+	// If it is nil, they fail.
+	Edit func(e *Edit)
 }
 
 // NewTemplate creates new a template with funcmap.
 func NewTemplate(td *TempalteData) *template.Template {
-	prefix := td.Pkg.Name()
+	prefix := "program"
+	if td.Pkg != nil {
+		prefix = td.Pkg.Name()
+	}
 	return template.New(prefix + "_format").Funcs(newFuncMap(td))
 }
 
 func newFuncMap(td *TempalteData) template.FuncMap {
 	var cmaps comment.Maps
+	u := td.universe()
 	return template.FuncMap{
-		"pkg":        func() *Package { return NewPackage(td.Pkg) },
+		"pkg":        func() *Package { return u.Package(td.Pkg) },
 		"br":         fmt.Sprintln,
 		"array":      ToArray,
 		"basic":      ToBasic,
@@ -48,23 +70,241 @@ func newFuncMap(td *TempalteData) template.FuncMap {
 		"signature":  ToSignature,
 		"slice":      ToSlice,
 		"struct":     ToStruct,
+		"typeparam":  ToTypeParam,
+		"union":      ToUnion,
+		"alias":      ToAlias,
 		"len":        lenFunc,
 		"cap":        capFunc,
 		"last":       lastFunc,
 		"exported":   Exported,
-		"methods":    Methods,
+		"methods":    u.Methods,
+		"methodSet":  u.MethodSet,
 		"names":      td.names,
 		"implements": implements,
 		"identical":  identical,
-		"under":      func(v any) *Type { return NewType(under(v)) },
+		"satisfies":  satisfies,
+		"typeset":    u.TypeSet,
+		"under":      func(v any) *Type { return u.Type(under(v)) },
+		"unalias":    func(v any) *Type { return u.Type(unalias(v)) },
 		"pos":        func(v any) token.Position { return Position(td.Fset, v) },
-		"objectof":   func(s string) Object { return td.objectOf(s) },
-		"typeof":     func(s string) *Type { return td.typeOf(s) },
+		"objectof":   func(s string) Object { return td.objectOf(u, s) },
+		"typeof":     func(s string) *Type { return td.typeOf(u, s) },
 		"doc":        func(v any) string { return td.doc(cmaps, v) },
 		"data":       func(k string) any { return td.Extra[k] },
 		"regexp":     regexpMatch,
 		"godoc":      godoc,
+		"implementers": func(iface any, deps ...bool) []*Implementation {
+			return Implementers(td.packages(u), iface, slices.Contains(deps, true))
+		},
+		"interfacesOf": func(typ any, deps ...bool) []*Implementation {
+			return InterfacesOf(td.packages(u), typ, slices.Contains(deps, true))
+		},
+		"refs":    func(v any) []*Ref { return td.refs(u, v) },
+		"callers": func(f any) ([]*Func, error) { return td.callers(u, f) },
+		"callees": func(f any) ([]*Func, error) { return td.callees(u, f) },
+		"reaches": func(from, to any) (bool, error) { return td.reaches(u, from, to) },
+		"graph":   importGraph,
+		"why":     func(path string) []*ImportPath { return td.why(u, path) },
+		"json":    td.json,
+		"diag":    td.diag,
+		"report":  td.diag,
+		"replace": func(v any, text string) (string, error) {
+			return td.edit("replace", v, text, false)
+		},
+		"insertBefore": func(v any, text string) (string, error) {
+			return td.edit("insertBefore", v, text, true)
+		},
+		"delete": func(v any) (string, error) {
+			return td.edit("delete", v, "", false)
+		},
+		"instantiate":    u.Instantiate,
+		"promotedFields": u.PromotedFields,
+		"missingMethods": u.MissingMethods,
+	}
+}
+
+// edit proposes an edit which replaces the range of v with text.
+// If insert is true, text is inserted before v.
+// v is an [*ASTNode], a value which has Pos and End methods or an object whose name is edited.
+// It returns an empty string so that it does not change the output.
+func (td *TempalteData) edit(name string, v any, text string, insert bool) (string, error) {
+	if td.Edit == nil {
+		return "", fmt.Errorf("%s: edits are not collected: use knife rewrite", name)
+	}
+
+	pos, end, err := editRange(v)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
 	}
+
+	if insert {
+		end = pos
+	}
+
+	td.Edit(&Edit{Pos: pos, End: end, NewText: text})
+	return "", nil
+}
+
+// diag reports a diagnostic at the position of v which has a Pos method.
+// severity is optional and it is "error", "warning" or "info" (see [Severity.Set]).
+// It returns an empty string so that it does not change the output.
+func (td *TempalteData) diag(v any, msg string, severity ...string) (string, error) {
+	if td.Report == nil {
+		return "", errors.New("diag: diagnostics are not collected")
+	}
+
+	n, ok := v.(interface{ Pos() token.Pos })
+	if !ok {
+		return "", fmt.Errorf("diag: %T does not have a position", v)
+	}
+
+	d := &Diagnostic{
+		Pos:     Position(td.Fset, v),
+		Message: msg,
+	}
+
+	if len(severity) > 0 {
+		if err := d.Severity.Set(severity[0]); err != nil {
+			return "", fmt.Errorf("diag: %w", err)
+		}
+	}
+
+	td.Report(n.Pos(), d)
+	return "", nil
+}
+
+// json encodes v into indented JSON with [MarshalJSON].
+// depth is an optional limit of nesting (see [JSONOption.Depth]).
+func (td *TempalteData) json(v any, depth ...int) (string, error) {
+	opt := &JSONOption{Fset: td.Fset, Indent: "  "}
+	if len(depth) > 0 {
+		opt.Depth = depth[0]
+	}
+
+	b, err := MarshalJSON(v, opt)
+	if err != nil {
+		return "", fmt.Errorf("json: %w", err)
+	}
+	return string(b), nil
+}
+
+// importGraph returns an import graph of v in the format.
+// v is a [*Package], a []*Package or a [*Program].
+// opts are options such as "module" and "depth=1" (see [ImportGraphOption.Set]).
+func importGraph(format string, v any, opts ...string) (string, error) {
+	var roots []*Package
+	switch v := v.(type) {
+	case *Package:
+		roots = []*Package{v}
+	case []*Package:
+		roots = v
+	case *Program:
+		roots = v.Packages
+	default:
+		return "", fmt.Errorf("graph: unexpected type %T: expected a package or a program", v)
+	}
+
+	var f GraphFormat
+	if err := f.Set(format); err != nil {
+		return "", fmt.Errorf("graph: %w", err)
+	}
+
+	var opt ImportGraphOption
+	for _, o := range opts {
+		if err := opt.Set(o); err != nil {
+			return "", fmt.Errorf("graph: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := NewImportGraph(roots, &opt).Write(&buf, f); err != nil {
+		return "", fmt.Errorf("graph: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (td *TempalteData) callGraph() (*CallGraph, error) {
+	if td.CallGraph == nil {
+		return nil, errors.New("call graph is not built: use -callgraph option")
+	}
+	return td.CallGraph, nil
+}
+
+// funcOf returns a function which is specified by an object or a qualified name.
+func (td *TempalteData) funcOf(u *Universe, f any) any {
+	name, ok := f.(string)
+	if !ok {
+		return f
+	}
+
+	if td.Program != nil {
+		return td.Program.Lookup(name)
+	}
+	return td.objectOf(u, name)
+}
+
+func (td *TempalteData) callers(u *Universe, f any) ([]*Func, error) {
+	cg, err := td.callGraph()
+	if err != nil {
+		return nil, err
+	}
+	return cg.Callers(td.funcOf(u, f)), nil
+}
+
+func (td *TempalteData) callees(u *Universe, f any) ([]*Func, error) {
+	cg, err := td.callGraph()
+	if err != nil {
+		return nil, err
+	}
+	return cg.Callees(td.funcOf(u, f)), nil
+}
+
+func (td *TempalteData) reaches(u *Universe, from, to any) (bool, error) {
+	cg, err := td.callGraph()
+	if err != nil {
+		return false, err
+	}
+	return cg.Reaches(td.funcOf(u, from), td.funcOf(u, to)), nil
+}
+
+// refs returns references to the object.
+// v is an object or a qualified name such as "net/http.Request.URL".
+func (td *TempalteData) refs(u *Universe, v any) []*Ref {
+	return u.Refs(td.Packages, td.funcOf(u, v))
+}
+
+// why returns shortest import paths from the package of the template to the package.
+// If the template is executed over all packages, the paths are from all packages.
+func (td *TempalteData) why(u *Universe, path string) []*ImportPath {
+	pkgs := td.Packages
+	if td.Pkg != nil {
+		pkgs = nil
+		for _, pkg := range td.Packages {
+			if pkg.Types == td.Pkg {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+	}
+	return u.Why(pkgs, path)
+}
+
+// packages returns all packages of the program.
+// If the program is not set, it returns the package of the template.
+func (td *TempalteData) packages(u *Universe) []*Package {
+	switch {
+	case td.Program != nil:
+		return td.Program.Packages
+	case td.Pkg != nil:
+		return []*Package{u.Package(td.Pkg)}
+	}
+	return nil
+}
+
+func (td *TempalteData) universe() *Universe {
+	if td.Universe == nil {
+		return NewUniverse()
+	}
+	return td.Universe
 }
 
 func (td *TempalteData) names(slice any) string {
@@ -118,45 +358,52 @@ func (td *TempalteData) name(v reflect.Value) string {
 	return ""
 }
 
-func (td *TempalteData) objectOf(s string) Object {
+func (td *TempalteData) objectOf(u *Universe, s string) Object {
 	dotPos := strings.LastIndex(s, ".")
 
 	if dotPos == -1 {
 		obj := types.Universe.Lookup(s)
-		return NewObject(obj)
+		return u.Object(obj)
 	}
 
 	pkg, name := s[:dotPos], s[dotPos+1:]
+	if td.Pkg == nil {
+		if td.Program == nil {
+			return nil
+		}
+		return u.Object(td.Program.lookup(pkg, name))
+	}
+
 	obj := analysisutil.LookupFromImports(td.Pkg.Imports(), pkg, name)
 	if obj != nil {
-		return NewObject(obj)
+		return u.Object(obj)
 	}
 
 	if analysisutil.RemoveVendor(td.Pkg.Name()) != analysisutil.RemoveVendor(pkg) {
 		return nil
 	}
 
-	return NewObject(td.Pkg.Scope().Lookup(name))
+	return u.Object(td.Pkg.Scope().Lookup(name))
 }
 
-func (td *TempalteData) typeOf(s string) *Type {
+func (td *TempalteData) typeOf(u *Universe, s string) *Type {
 	if s == "" {
 		return nil
 	}
 
 	if s[0] == '*' {
-		typ := td.typeOf(s[1:])
+		typ := td.typeOf(u, s[1:])
 		if typ == nil {
 			return nil
 		}
-		return NewType(types.NewPointer(typ.TypesType))
+		return u.Type(types.NewPointer(typ.TypesType))
 	}
 
-	obj := td.objectOf(s)
+	obj := td.objectOf(u, s)
 	if obj == nil {
 		return nil
 	}
-	return NewType(obj.TypesObject().Type())
+	return u.Type(obj.TypesObject().Type())
 }
 
 func (td *TempalteData) doc(cmaps comment.Maps, v any) string {
@@ -0,0 +1,19 @@
+package a
+
+import "io"
+
+type Base struct {
+	Name string
+}
+
+func (b *Base) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+type A = Base
+
+type B = A
+
+type R = io.Reader
+
+type Ints = []int
@@ -0,0 +1,7 @@
+//go:build go1.24
+
+package a
+
+type Set[T comparable] = map[T]bool
+
+var IntSet Set[int]
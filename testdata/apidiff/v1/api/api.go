@@ -0,0 +1,31 @@
+package api
+
+import "io"
+
+const Version = 1
+
+const Name = "api"
+
+type Config struct {
+	Timeout int
+	Debug   bool
+}
+
+func (c *Config) Validate() error { return nil }
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+type sealed interface {
+	seal()
+}
+
+type Sealed interface {
+	sealed
+	Name() string
+}
+
+func Open(name string) (io.Reader, error) { return nil, nil }
+
+func Close() {}
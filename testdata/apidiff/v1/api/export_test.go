@@ -0,0 +1,6 @@
+package api
+
+// ExportedForTest is exported only for tests.
+func ExportedForTest() {}
+
+func TestValidate(t interface{ Fatal(...any) }) {}
@@ -0,0 +1,3 @@
+module example.com/apidiff
+
+go 1.23
@@ -0,0 +1,34 @@
+package api
+
+import "io"
+
+const Version = 2
+
+const Name = "api"
+
+type Config struct {
+	Timeout int
+	Debug   bool
+	Retry   int
+}
+
+func (c Config) Validate() error { return nil }
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+}
+
+type sealed interface {
+	seal()
+}
+
+type Sealed interface {
+	sealed
+	Name() string
+	Kind() int
+}
+
+func Open(name string, opts ...string) (io.Reader, error) { return nil, nil }
+
+func Flush() {}
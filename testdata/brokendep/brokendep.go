@@ -0,0 +1,8 @@
+package brokendep
+
+import "github.com/gostaticanalysis/knife/testdata/broken"
+
+// G has no errors but the imported package has a type error.
+func G() int {
+	return broken.F()
+}
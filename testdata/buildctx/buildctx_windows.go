@@ -0,0 +1,3 @@
+package buildctx
+
+func Windows() {}
@@ -0,0 +1,33 @@
+package callgraph
+
+import (
+	"log"
+	"os"
+)
+
+type Logger interface {
+	Log(msg string)
+}
+
+type exitLogger struct{}
+
+func NewExitLogger() Logger { return exitLogger{} }
+
+func (exitLogger) Log(msg string) { fatal() }
+
+func fatal() { os.Exit(1) }
+
+// Deprecated: use Run.
+func helper() {}
+
+func Run(l Logger) {
+	func() {
+		l.Log("run")
+	}()
+	helper()
+}
+
+func Safe() { helper() }
+
+// Die reaches os.Exit through log.Fatal in the standard library.
+func Die() { log.Fatal("die") }
@@ -0,0 +1,58 @@
+package a
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type List[T any] struct {
+	items []T
+}
+
+func (l *List[T]) Push(v T) {
+	l.items = append(l.items, v)
+}
+
+func (l *List[T]) Len() int {
+	return len(l.items)
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+	ys := make([]U, len(xs))
+	for i, x := range xs {
+		ys[i] = f(x)
+	}
+	return ys
+}
+
+func Sum[T Number](xs []T) T {
+	var s T
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+func Bytes[T ~[]byte](b T) int {
+	return len(b)
+}
+
+var IntList List[int]
+
+type Integer interface {
+	Number
+	~int | ~int64 | ~uint
+}
+
+type Cache[K comparable, V any] struct {
+	Entries map[K]V
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.Entries[key]
+	return v, ok
+}
@@ -0,0 +1,5 @@
+package x
+
+import "github.com/gostaticanalysis/knife/testdata/graph/b"
+
+var X = b.B
@@ -0,0 +1,5 @@
+package y
+
+import "strings"
+
+var Y = strings.ToUpper("y")
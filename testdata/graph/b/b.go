@@ -0,0 +1,5 @@
+package b
+
+import "github.com/gostaticanalysis/knife/testdata/graph/a/y"
+
+var B = y.Y
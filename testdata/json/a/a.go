@@ -0,0 +1,24 @@
+package a
+
+import "io"
+
+// Node is a node of a linked list.
+type Node struct {
+	Value int `json:"value"`
+	Next  *Node
+}
+
+func (n *Node) Len() int {
+	if n == nil {
+		return 0
+	}
+	return 1 + n.Next.Len()
+}
+
+var Head *Node
+
+const Max = 10
+
+func Copy(w io.Writer, n *Node) error {
+	return nil
+}
@@ -0,0 +1,13 @@
+package a
+
+import (
+	"io"
+
+	"github.com/gostaticanalysis/comment"
+)
+
+var R io.Reader = nil
+
+func Comments(m comment.Maps) int {
+	return len(m)
+}
@@ -0,0 +1,41 @@
+package a
+
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+type Base struct {
+	Logger
+	ID   int
+	Name string
+}
+
+func (b Base) Describe() string {
+	return b.Name
+}
+
+func (b *Base) SetName(name string) {
+	b.Name = name
+}
+
+type Label struct {
+	Name string
+}
+
+func (l *Label) SetName(name string) {
+	l.Name = name
+}
+
+type Service struct {
+	Base
+	Label
+	Name string
+}
+
+func (s *Service) Close() error {
+	return nil
+}
+
+type Handler struct {
+	*Base
+}
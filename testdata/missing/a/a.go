@@ -0,0 +1,56 @@
+package a
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+	Close() error
+}
+
+// Memory implements Store with pointer receivers.
+type Memory struct {
+	m map[string]string
+}
+
+func (s *Memory) Get(key string) (string, error) {
+	return s.m[key], nil
+}
+
+func (s *Memory) Put(key, value string) error {
+	s.m[key] = value
+	return nil
+}
+
+func (s *Memory) Close() error {
+	return nil
+}
+
+// File has a wrong Put and does not have Close.
+type File struct{}
+
+func (File) Get(key string) (string, error) {
+	return "", nil
+}
+
+func (File) Put(key string, value []byte) error {
+	return nil
+}
+
+// Disk has a wrong Put with a pointer receiver.
+type Disk struct{}
+
+func (*Disk) Get(key string) (string, error) {
+	return "", nil
+}
+
+func (*Disk) Put(key string, value []byte) error {
+	return nil
+}
+
+func (*Disk) Close() error {
+	return nil
+}
+
+// Cached implements Store with the embedded *Memory.
+type Cached struct {
+	*Memory
+}
@@ -0,0 +1,7 @@
+package b
+
+type Buffer struct{}
+
+func (Buffer) Read(p []byte) (int, error) { return 0, nil }
+
+type Config struct{}
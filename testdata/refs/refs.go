@@ -0,0 +1,21 @@
+package refs
+
+type Config struct {
+	Timeout int
+}
+
+type Server struct {
+	Config
+	Name string
+}
+
+func (c *Config) SetTimeout(t int) { c.Timeout = t }
+
+func NewServer() *Server {
+	s := &Server{Config: Config{Timeout: 10}}
+	s.Timeout++
+	s.SetTimeout(s.Timeout)
+	return s
+}
+
+var DefaultTimeout = Config{}.Timeout
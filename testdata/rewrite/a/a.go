@@ -0,0 +1,11 @@
+package a
+
+import "fmt"
+
+func Hello() {
+	fmt.Println("hello")
+}
+
+func Bye() {
+	fmt.Println("bye")
+}
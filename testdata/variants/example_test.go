@@ -0,0 +1,3 @@
+package variants_test
+
+func Example() {}
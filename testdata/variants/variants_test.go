@@ -0,0 +1,3 @@
+package variants
+
+func helper() {}
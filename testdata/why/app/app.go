@@ -0,0 +1,8 @@
+package app
+
+import (
+	"github.com/gostaticanalysis/knife/testdata/why/l1"
+	"github.com/gostaticanalysis/knife/testdata/why/l2"
+)
+
+var _, _ = l1.L1, l2.L2
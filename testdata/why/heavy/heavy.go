@@ -0,0 +1,3 @@
+package heavy
+
+var Heavy = 1
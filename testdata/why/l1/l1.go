@@ -0,0 +1,5 @@
+package l1
+
+import "github.com/gostaticanalysis/knife/testdata/why/heavy"
+
+var L1 = heavy.Heavy
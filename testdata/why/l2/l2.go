@@ -0,0 +1,8 @@
+package l2
+
+import (
+	_ "github.com/gostaticanalysis/knife/testdata/why/heavy"
+	"github.com/gostaticanalysis/knife/testdata/why/l1"
+)
+
+var L2 = l1.L1
@@ -3,31 +3,48 @@ package knife
 import (
 	"fmt"
 	"go/types"
+	"sync"
 )
 
 type Type struct {
 	TypesType types.Type
+	u         *Universe
 }
 
 var _ fmt.Stringer = (*Type)(nil)
 
+// NewType creates a [Type] in a new [Universe].
 func NewType(t types.Type) *Type {
+	return NewUniverse().Type(t)
+}
+
+// Type returns a [Type] of t in the universe.
+func (u *Universe) Type(t types.Type) *Type {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newType(t)
+}
+
+func (u *Universe) newType(t types.Type) *Type {
 	if t == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(t)
-	cached, _ := v.(*Type)
-	if cached != nil {
-		return cached
+	return load(u, t, func(nt *Type) {
+		nt.TypesType = t
+		nt.u = u
+	})
+}
+
+func (t *Type) universe() *Universe {
+	if t.u == nil {
+		return NewUniverse()
 	}
-	nt := &Type{TypesType: t}
-	cache.Store(t, nt)
-	return nt
+	return t.u
 }
 
 func (t *Type) Underlying() *Type {
-	return NewType(t.TypesType.Underlying())
+	return t.universe().Type(t.TypesType.Underlying())
 }
 
 func (t *Type) String() string {
@@ -36,52 +53,91 @@ func (t *Type) String() string {
 
 func (t *Type) Array() *Array {
 	a, _ := under(t.TypesType).(*types.Array)
-	return NewArray(a)
+	return t.universe().Array(a)
 }
 
 func (t *Type) Slice() *Slice {
 	s, _ := under(t.TypesType).(*types.Slice)
-	return NewSlice(s)
+	return t.universe().Slice(s)
 }
 
 func (t *Type) Struct() *Struct {
 	s, _ := under(t.TypesType).(*types.Struct)
-	return NewStruct(s)
+	return t.universe().Struct(s)
 }
 
 func (t *Type) Map() *Map {
 	m, _ := under(t.TypesType).(*types.Map)
-	return NewMap(m)
+	return t.universe().Map(m)
 }
 
 func (t *Type) Pointer() *Pointer {
 	p, _ := under(t.TypesType).(*types.Pointer)
-	return NewPointer(p)
+	return t.universe().Pointer(p)
 }
 
 func (t *Type) Chan() *Chan {
 	c, _ := under(t.TypesType).(*types.Chan)
-	return NewChan(c)
+	return t.universe().Chan(c)
 }
 
 func (t *Type) Basic() *Basic {
 	b, _ := under(t.TypesType).(*types.Basic)
-	return NewBasic(b)
+	return t.universe().Basic(b)
 }
 
 func (t *Type) Interface() *Interface {
 	i, _ := under(t.TypesType).(*types.Interface)
-	return NewInterface(i)
+	return t.universe().Interface(i)
 }
 
 func (t *Type) Signature() *Signature {
 	s, _ := under(t.TypesType).(*types.Signature)
-	return NewSignature(s)
+	return t.universe().Signature(s)
 }
 
+// Named returns the defined type or nil if the type is not a defined type.
+// An alias of a defined type is resolved to the defined type.
 func (t *Type) Named() *Named {
-	n, _ := t.TypesType.(*types.Named)
-	return NewNamed(n)
+	n, _ := types.Unalias(t.TypesType).(*types.Named)
+	return t.universe().Named(n)
+}
+
+// Alias returns the alias or nil if the type is not an alias such as A of type A = B.
+func (t *Type) Alias() *Alias {
+	a, _ := t.TypesType.(*types.Alias)
+	return t.universe().Alias(a)
+}
+
+// Unalias returns the actual type which the type refers through aliases.
+// It returns the type itself if the type is not an alias.
+func (t *Type) Unalias() *Type {
+	return t.universe().Type(types.Unalias(t.TypesType))
+}
+
+// TypeParam returns the type parameter or nil if the type is not a type parameter.
+func (t *Type) TypeParam() *TypeParam {
+	tp, _ := types.Unalias(t.TypesType).(*types.TypeParam)
+	return t.universe().TypeParam(tp)
+}
+
+// Union returns the union or nil if the type is not a union
+// or an interface which embeds only one union.
+func (t *Type) Union() *Union {
+	switch typ := types.Unalias(t.TypesType).(type) {
+	case *types.Union:
+		return t.universe().Union(typ)
+	case *types.TypeParam:
+		return t.universe().Type(typ.Constraint()).Union()
+	}
+
+	iface, _ := t.TypesType.Underlying().(*types.Interface)
+	if iface == nil || iface.NumEmbeddeds() != 1 {
+		return nil
+	}
+
+	un, _ := iface.EmbeddedType(0).(*types.Union)
+	return t.universe().Union(un)
 }
 
 type Array struct {
@@ -92,29 +148,36 @@ type Array struct {
 
 var _ fmt.Stringer = (*Array)(nil)
 
+// NewArray creates an [Array] in a new [Universe].
 func NewArray(a *types.Array) *Array {
+	return NewUniverse().Array(a)
+}
+
+// Array returns an [Array] of a in the universe.
+func (u *Universe) Array(a *types.Array) *Array {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newArray(a)
+}
+
+func (u *Universe) newArray(a *types.Array) *Array {
 	if a == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(a)
-	cached, _ := v.(*Array)
-	if cached != nil {
-		return cached
-	}
-
-	var na Array
-	na.TypesArray = a
-	na.Elem = NewType(a.Elem())
-	na.Len = a.Len()
-	cache.Store(a, &na)
-	return &na
+	return load(u, a, func(na *Array) {
+		na.TypesArray = a
+		na.Elem = u.newType(a.Elem())
+		na.Len = a.Len()
+	})
 }
 
 func ToArray(t any) *Array {
 	switch t := t.(type) {
 	case *Type:
 		return t.Array()
+	case *TypeName:
+		return t.Type.Array()
 	case types.Type:
 		return NewType(t).Array()
 	}
@@ -132,29 +195,35 @@ type Slice struct {
 
 var _ fmt.Stringer = (*Slice)(nil)
 
+// NewSlice creates a [Slice] in a new [Universe].
 func NewSlice(s *types.Slice) *Slice {
+	return NewUniverse().Slice(s)
+}
+
+// Slice returns a [Slice] of s in the universe.
+func (u *Universe) Slice(s *types.Slice) *Slice {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newSlice(s)
+}
+
+func (u *Universe) newSlice(s *types.Slice) *Slice {
 	if s == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(s)
-	cached, _ := v.(*Slice)
-	if cached != nil {
-		return cached
-	}
-
-	var ns Slice
-	ns.TypesSlice = s
-	ns.Elem = NewType(s.Elem())
-
-	cache.Store(s, &ns)
-	return &ns
+	return load(u, s, func(ns *Slice) {
+		ns.TypesSlice = s
+		ns.Elem = u.newType(s.Elem())
+	})
 }
 
 func ToSlice(t any) *Slice {
 	switch t := t.(type) {
 	case *Type:
 		return t.Slice()
+	case *TypeName:
+		return t.Type.Slice()
 	case types.Type:
 		return NewType(t).Slice()
 	}
@@ -165,38 +234,65 @@ func (s *Slice) String() string {
 	return s.TypesSlice.String()
 }
 
+// Struct is a struct type.
+// Fields of the struct are created on first access.
 type Struct struct {
 	TypesStruct *types.Struct
-	Fields      map[string]*Field
-	FieldNames  []string
+
+	u          *Universe
+	fieldsOnce sync.Once
+	fields     map[string]*Field
+	fieldNames []string
 }
 
 var _ fmt.Stringer = (*Struct)(nil)
 
+// NewStruct creates a [Struct] in a new [Universe].
 func NewStruct(s *types.Struct) *Struct {
+	return NewUniverse().Struct(s)
+}
+
+// Struct returns a [Struct] of s in the universe.
+func (u *Universe) Struct(s *types.Struct) *Struct {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newStruct(s)
+}
+
+func (u *Universe) newStruct(s *types.Struct) *Struct {
 	if s == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(s)
-	cached, _ := v.(*Struct)
-	if cached != nil {
-		return cached
-	}
+	return load(u, s, func(ns *Struct) {
+		ns.TypesStruct = s
+		ns.u = u
+	})
+}
 
-	var ns Struct
-	cache.Store(s, &ns)
-	ns.TypesStruct = s
-	ns.Fields = make(map[string]*Field, s.NumFields())
-	ns.FieldNames = make([]string, s.NumFields())
+func (s *Struct) loadFields() {
+	s.fieldsOnce.Do(func() {
+		ts := s.TypesStruct
+		s.fields = make(map[string]*Field, ts.NumFields())
+		s.fieldNames = make([]string, ts.NumFields())
+		for i := 0; i < ts.NumFields(); i++ {
+			v := ts.Field(i)
+			s.fields[v.Name()] = s.u.Field(s, v, ts.Tag(i))
+			s.fieldNames[i] = v.Name()
+		}
+	})
+}
 
-	for i := 0; i < s.NumFields(); i++ {
-		v := s.Field(i)
-		ns.Fields[v.Name()] = NewField(&ns, v, s.Tag(i))
-		ns.FieldNames[i] = v.Name()
-	}
+// Fields returns fields of the struct by their names.
+func (s *Struct) Fields() map[string]*Field {
+	s.loadFields()
+	return s.fields
+}
 
-	return &ns
+// FieldNames returns names of the fields in declaration order.
+func (s *Struct) FieldNames() []string {
+	s.loadFields()
+	return s.fieldNames
 }
 
 func ToStruct(t any) *Struct {
@@ -211,7 +307,7 @@ func ToStruct(t any) *Struct {
 	return nil
 }
 
-func (s Struct) String() string {
+func (s *Struct) String() string {
 	return s.TypesStruct.String()
 }
 
@@ -223,24 +319,28 @@ type Map struct {
 
 var _ fmt.Stringer = (*Map)(nil)
 
+// NewMap creates a [Map] in a new [Universe].
 func NewMap(m *types.Map) *Map {
+	return NewUniverse().Map(m)
+}
+
+// Map returns a [Map] of m in the universe.
+func (u *Universe) Map(m *types.Map) *Map {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newMap(m)
+}
+
+func (u *Universe) newMap(m *types.Map) *Map {
 	if m == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(m)
-	cached, _ := v.(*Map)
-	if cached != nil {
-		return cached
-	}
-
-	var nm Map
-	nm.TypesMap = m
-	nm.Elem = NewType(m.Elem())
-	nm.Key = NewType(m.Key())
-
-	cache.Store(m, &nm)
-	return &nm
+	return load(u, m, func(nm *Map) {
+		nm.TypesMap = m
+		nm.Elem = u.newType(m.Elem())
+		nm.Key = u.newType(m.Key())
+	})
 }
 
 func ToMap(t any) *Map {
@@ -266,23 +366,27 @@ type Pointer struct {
 
 var _ fmt.Stringer = (*Pointer)(nil)
 
+// NewPointer creates a [Pointer] in a new [Universe].
 func NewPointer(p *types.Pointer) *Pointer {
+	return NewUniverse().Pointer(p)
+}
+
+// Pointer returns a [Pointer] of p in the universe.
+func (u *Universe) Pointer(p *types.Pointer) *Pointer {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newPointer(p)
+}
+
+func (u *Universe) newPointer(p *types.Pointer) *Pointer {
 	if p == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(p)
-	cached, _ := v.(*Pointer)
-	if cached != nil {
-		return cached
-	}
-
-	var np Pointer
-	np.TypesPointer = p
-	np.Elem = NewType(p.Elem())
-
-	cache.Store(p, &np)
-	return &np
+	return load(u, p, func(np *Pointer) {
+		np.TypesPointer = p
+		np.Elem = u.newType(p.Elem())
+	})
 }
 
 func ToPointer(t any) *Pointer {
@@ -309,24 +413,28 @@ type Chan struct {
 
 var _ fmt.Stringer = (*Chan)(nil)
 
+// NewChan creates a [Chan] in a new [Universe].
 func NewChan(c *types.Chan) *Chan {
+	return NewUniverse().Chan(c)
+}
+
+// Chan returns a [Chan] of c in the universe.
+func (u *Universe) Chan(c *types.Chan) *Chan {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newChan(c)
+}
+
+func (u *Universe) newChan(c *types.Chan) *Chan {
 	if c == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(c)
-	cached, _ := v.(*Chan)
-	if cached != nil {
-		return cached
-	}
-
-	var nc Chan
-	nc.TypesChan = c
-	nc.Dir = c.Dir()
-	nc.Elem = NewType(c.Elem())
-
-	cache.Store(c, &nc)
-	return &nc
+	return load(u, c, func(nc *Chan) {
+		nc.TypesChan = c
+		nc.Dir = c.Dir()
+		nc.Elem = u.newType(c.Elem())
+	})
 }
 
 func ToChan(t any) *Chan {
@@ -354,26 +462,29 @@ type Basic struct {
 
 var _ fmt.Stringer = (*Basic)(nil)
 
+// NewBasic creates a [Basic] in a new [Universe].
 func NewBasic(b *types.Basic) *Basic {
-	if b == nil {
-		return nil
-	}
+	return NewUniverse().Basic(b)
+}
 
-	v, _ := cache.Load(b)
-	cached, _ := v.(*Basic)
-	if cached != nil {
-		return cached
-	}
+// Basic returns a [Basic] of b in the universe.
+func (u *Universe) Basic(b *types.Basic) *Basic {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newBasic(b)
+}
 
-	nb := &Basic{
-		TypesBasic: b,
-		Info:       b.Info(),
-		Kind:       b.Kind(),
-		Name:       b.Name(),
+func (u *Universe) newBasic(b *types.Basic) *Basic {
+	if b == nil {
+		return nil
 	}
 
-	cache.Store(b, nb)
-	return nb
+	return load(u, b, func(nb *Basic) {
+		nb.TypesBasic = b
+		nb.Info = b.Info()
+		nb.Kind = b.Kind()
+		nb.Name = b.Name()
+	})
 }
 
 func ToBasic(t any) *Basic {
@@ -392,56 +503,103 @@ func (b *Basic) String() string {
 	return b.TypesBasic.String()
 }
 
+// Interface is an interface type.
+// Embedded types and methods of the interface are created on first access.
 type Interface struct {
-	TypesInterface      *types.Interface
-	Empty               bool
-	Embeddeds           []*Type
-	Methods             map[string]*Func
-	MethodNames         []string
-	ExplicitMethods     map[string]*Func
-	ExplicitMethodNames []string
+	TypesInterface *types.Interface
+	Empty          bool
+
+	u                   *Universe
+	once                sync.Once
+	embeddeds           []*Type
+	methods             map[string]*Func
+	methodNames         []string
+	explicitMethods     map[string]*Func
+	explicitMethodNames []string
 }
 
 var _ fmt.Stringer = (*Interface)(nil)
 
+// NewInterface creates an [Interface] in a new [Universe].
 func NewInterface(iface *types.Interface) *Interface {
+	return NewUniverse().Interface(iface)
+}
+
+// Interface returns an [Interface] of iface in the universe.
+func (u *Universe) Interface(iface *types.Interface) *Interface {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newInterface(iface)
+}
+
+func (u *Universe) newInterface(iface *types.Interface) *Interface {
 	if iface == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(iface)
-	cached, _ := v.(*Interface)
-	if cached != nil {
-		return cached
-	}
+	return load(u, iface, func(ni *Interface) {
+		ni.TypesInterface = iface
+		ni.Empty = iface.Empty()
+		ni.u = u
+	})
+}
 
-	var ni Interface
-	cache.Store(iface, &ni)
-	ni.TypesInterface = iface
-	ni.Empty = iface.Empty()
-	ni.Embeddeds = make([]*Type, iface.NumEmbeddeds())
-	ni.Methods = make(map[string]*Func, iface.NumMethods())
-	ni.MethodNames = make([]string, iface.NumMethods())
-	ni.ExplicitMethods = make(map[string]*Func, iface.NumExplicitMethods())
-	ni.ExplicitMethodNames = make([]string, iface.NumExplicitMethods())
+func (i *Interface) load() {
+	i.once.Do(func() {
+		iface, u := i.TypesInterface, i.u
+		i.embeddeds = make([]*Type, iface.NumEmbeddeds())
+		i.methods = make(map[string]*Func, iface.NumMethods())
+		i.methodNames = make([]string, iface.NumMethods())
+		i.explicitMethods = make(map[string]*Func, iface.NumExplicitMethods())
+		i.explicitMethodNames = make([]string, iface.NumExplicitMethods())
+
+		for j := 0; j < iface.NumEmbeddeds(); j++ {
+			i.embeddeds[j] = u.Type(iface.EmbeddedType(j))
+		}
 
-	for i := 0; i < iface.NumEmbeddeds(); i++ {
-		ni.Embeddeds[i] = NewType(iface.EmbeddedType(i))
-	}
+		for j := 0; j < iface.NumMethods(); j++ {
+			m := iface.Method(j)
+			i.methods[m.Name()] = u.Func(m)
+			i.methodNames[j] = m.Name()
+		}
 
-	for i := 0; i < iface.NumMethods(); i++ {
-		m := iface.Method(i)
-		ni.Methods[m.Name()] = NewFunc(m)
-		ni.MethodNames[i] = m.Name()
-	}
+		for j := 0; j < iface.NumExplicitMethods(); j++ {
+			m := iface.ExplicitMethod(j)
+			i.explicitMethods[m.Name()] = u.Func(m)
+			i.explicitMethodNames[j] = m.Name()
+		}
+	})
+}
 
-	for i := 0; i < iface.NumExplicitMethods(); i++ {
-		m := iface.ExplicitMethod(i)
-		ni.ExplicitMethods[m.Name()] = NewFunc(m)
-		ni.ExplicitMethodNames[i] = m.Name()
-	}
+// Embeddeds returns embedded types of the interface.
+func (i *Interface) Embeddeds() []*Type {
+	i.load()
+	return i.embeddeds
+}
+
+// Methods returns all methods of the interface by their names
+// including methods of embedded interfaces.
+func (i *Interface) Methods() map[string]*Func {
+	i.load()
+	return i.methods
+}
 
-	return &ni
+// MethodNames returns sorted names of all methods of the interface.
+func (i *Interface) MethodNames() []string {
+	i.load()
+	return i.methodNames
+}
+
+// ExplicitMethods returns explicitly declared methods of the interface by their names.
+func (i *Interface) ExplicitMethods() map[string]*Func {
+	i.load()
+	return i.explicitMethods
+}
+
+// ExplicitMethodNames returns sorted names of explicitly declared methods of the interface.
+func (i *Interface) ExplicitMethodNames() []string {
+	i.load()
+	return i.explicitMethodNames
 }
 
 func ToInterface(t any) *Interface {
@@ -466,38 +624,53 @@ type Signature struct {
 	Params         []*Var
 	Results        []*Var
 	Variadic       bool
+	// TypeParams are type parameters of a generic function.
+	TypeParams []*TypeParam
+	// RecvTypeParams are type parameters of the receiver type of a method
+	// such as T of func (l *List[T]) Push(v T).
+	RecvTypeParams []*TypeParam
+	// Generic reports whether the signature is of a generic function or a method of a generic type.
+	Generic bool
 }
 
 var _ fmt.Stringer = (*Signature)(nil)
 
+// NewSignature creates a [Signature] in a new [Universe].
 func NewSignature(s *types.Signature) *Signature {
+	return NewUniverse().Signature(s)
+}
+
+// Signature returns a [Signature] of s in the universe.
+func (u *Universe) Signature(s *types.Signature) *Signature {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newSignature(s)
+}
+
+func (u *Universe) newSignature(s *types.Signature) *Signature {
 	if s == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(s)
-	cached, _ := v.(*Signature)
-	if cached != nil {
-		return cached
-	}
-
-	var ns Signature
-	cache.Store(s, ns)
-	ns.TypesSignature = s
-	ns.Recv = NewVar(s.Recv())
-	ns.Params = make([]*Var, s.Params().Len())
-	ns.Results = make([]*Var, s.Results().Len())
-	ns.Variadic = s.Variadic()
+	return load(u, s, func(ns *Signature) {
+		ns.TypesSignature = s
+		ns.Recv = u.newVar(s.Recv())
+		ns.Params = make([]*Var, s.Params().Len())
+		ns.Results = make([]*Var, s.Results().Len())
+		ns.Variadic = s.Variadic()
 
-	for i := 0; i < s.Params().Len(); i++ {
-		ns.Params[i] = NewVar(s.Params().At(i))
-	}
+		for i := 0; i < s.Params().Len(); i++ {
+			ns.Params[i] = u.newVar(s.Params().At(i))
+		}
 
-	for i := 0; i < s.Results().Len(); i++ {
-		ns.Results[i] = NewVar(s.Results().At(i))
-	}
+		for i := 0; i < s.Results().Len(); i++ {
+			ns.Results[i] = u.newVar(s.Results().At(i))
+		}
 
-	return &ns
+		ns.TypeParams = u.newTypeParams(s.TypeParams())
+		ns.RecvTypeParams = u.newTypeParams(s.RecvTypeParams())
+		ns.Generic = len(ns.TypeParams) > 0 || len(ns.RecvTypeParams) > 0
+	})
 }
 
 func ToSignature(t any) *Signature {
@@ -516,40 +689,81 @@ func (s *Signature) String() string {
 	return s.TypesSignature.String()
 }
 
+// Named is a defined type.
+// Methods of the type are created on first access.
 type Named struct {
-	TypesNamed  *types.Named
-	Methods     map[string]*Func
-	MethodNames []string
-	Object      *TypeName
+	TypesNamed *types.Named
+	Object     *TypeName
+	// TypeParams are type parameters of the generic type.
+	// An instantiated type has type parameters of its origin.
+	TypeParams []*TypeParam
+	// TypeArgs are type arguments of an instantiated type such as int of List[int].
+	TypeArgs []*Type
+	// Origin is the generic type of an instantiated type.
+	// It is the type itself if the type is not instantiated.
+	Origin *Named
+	// Generic reports whether the type has type parameters and it is not instantiated.
+	Generic bool
+
+	u           *Universe
+	methodsOnce sync.Once
+	methods     map[string]*Func
+	methodNames []string
 }
 
 var _ fmt.Stringer = (*Named)(nil)
 
+// NewNamed creates a [Named] in a new [Universe].
 func NewNamed(n *types.Named) *Named {
+	return NewUniverse().Named(n)
+}
+
+// Named returns a [Named] of n in the universe.
+func (u *Universe) Named(n *types.Named) *Named {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newNamed(n)
+}
+
+func (u *Universe) newNamed(n *types.Named) *Named {
 	if n == nil {
 		return nil
 	}
 
-	v, _ := cache.Load(n)
-	cached, _ := v.(*Named)
-	if cached != nil {
-		return cached
-	}
+	return load(u, n, func(nn *Named) {
+		nn.TypesNamed = n
+		nn.Object = u.newTypeName(n.Obj())
+		nn.TypeParams = u.newTypeParams(n.TypeParams())
+		nn.TypeArgs = u.newTypeArgs(n.TypeArgs())
+		nn.Origin = u.newNamed(n.Origin())
+		nn.Generic = len(nn.TypeParams) > 0 && len(nn.TypeArgs) == 0
+		nn.u = u
+	})
+}
 
-	var nn Named
-	cache.Store(n, &nn)
-	nn.TypesNamed = n
-	nn.Methods = make(map[string]*Func, n.NumMethods())
-	nn.MethodNames = make([]string, n.NumMethods())
-	nn.Object = NewTypeName(n.Obj())
+func (n *Named) loadMethods() {
+	n.methodsOnce.Do(func() {
+		tn := n.TypesNamed
+		n.methods = make(map[string]*Func, tn.NumMethods())
+		n.methodNames = make([]string, tn.NumMethods())
+		for i := 0; i < tn.NumMethods(); i++ {
+			m := tn.Method(i)
+			n.methods[m.Name()] = n.u.Func(m)
+			n.methodNames[i] = m.Name()
+		}
+	})
+}
 
-	for i := 0; i < n.NumMethods(); i++ {
-		m := n.Method(i)
-		nn.Methods[m.Name()] = NewFunc(m)
-		nn.MethodNames[i] = m.Name()
-	}
+// Methods returns methods which are declared with the type by their names.
+func (n *Named) Methods() map[string]*Func {
+	n.loadMethods()
+	return n.methods
+}
 
-	return &nn
+// MethodNames returns names of the methods in declaration order.
+func (n *Named) MethodNames() []string {
+	n.loadMethods()
+	return n.methodNames
 }
 
 func ToNamed(t any) *Named {
@@ -568,25 +782,112 @@ func (c *Named) String() string {
 	return c.TypesNamed.String()
 }
 
+// Alias is an alias type such as A of type A = B.
+type Alias struct {
+	TypesAlias *types.Alias
+	Object     *TypeName
+	// Rhs is the type on the right-hand side of the alias declaration.
+	// It may be another alias.
+	Rhs *Type
+	// Actual is the type which the alias refers through all aliases.
+	Actual *Type
+	// TypeParams are type parameters of the generic alias such as T of type Set[T comparable] = map[T]bool.
+	TypeParams []*TypeParam
+	// TypeArgs are type arguments of an instantiated alias such as int of Set[int].
+	TypeArgs []*Type
+	// Origin is the generic alias of an instantiated alias.
+	// It is the alias itself if the alias is not instantiated.
+	Origin *Alias
+	// Generic reports whether the alias has type parameters and it is not instantiated.
+	Generic bool
+}
+
+var _ fmt.Stringer = (*Alias)(nil)
+
+// NewAlias creates an [Alias] in a new [Universe].
+func NewAlias(a *types.Alias) *Alias {
+	return NewUniverse().Alias(a)
+}
+
+// Alias returns an [Alias] of a in the universe.
+func (u *Universe) Alias(a *types.Alias) *Alias {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.newAlias(a)
+}
+
+func (u *Universe) newAlias(a *types.Alias) *Alias {
+	if a == nil {
+		return nil
+	}
+
+	return load(u, a, func(na *Alias) {
+		na.TypesAlias = a
+		na.Object = u.newTypeName(a.Obj())
+		na.Rhs = u.newType(a.Rhs())
+		na.Actual = u.newType(types.Unalias(a))
+		na.TypeParams = u.newTypeParams(a.TypeParams())
+		na.TypeArgs = u.newTypeArgs(a.TypeArgs())
+		na.Origin = u.newAlias(a.Origin())
+		na.Generic = len(na.TypeParams) > 0 && len(na.TypeArgs) == 0
+	})
+}
+
+// ToAlias converts a type to an [Alias].
+// It returns nil if the type is not an alias.
+func ToAlias(t any) *Alias {
+	switch t := t.(type) {
+	case *Type:
+		return t.Alias()
+	case *TypeName:
+		return t.Type.Alias()
+	case types.Type:
+		return NewType(t).Alias()
+	}
+	return nil
+}
+
+func (a *Alias) String() string {
+	return a.TypesAlias.String()
+}
+
+// unalias returns the actual type of a type or an object through aliases.
+func unalias(v any) types.Type {
+	t := typeOfValue(v)
+	if t == nil {
+		return nil
+	}
+	return types.Unalias(t)
+}
+
+// Methods returns methods of the type in a new [Universe].
+// See [Universe.Methods].
 func Methods(v any) map[string]*Func {
+	return NewUniverse().Methods(v)
+}
+
+// Methods returns methods of the type which are included
+// in the method set of T or *T.
+func (u *Universe) Methods(v any) map[string]*Func {
 	methods := map[string]*Func{}
 	switch t := v.(type) {
 	case *Type:
-		return Methods(t.TypesType)
+		return u.Methods(t.TypesType)
 	case *TypeName:
-		return Methods(t.TypesTypeName.Type())
+		return u.Methods(t.TypesTypeName.Type())
 	case *types.TypeName:
-		return Methods(t.Type())
+		return u.Methods(t.Type())
 	case types.Type:
+		t = types.Unalias(t)
 		ms := types.NewMethodSet(t)
 		for i := 0; i < ms.Len(); i++ {
 			m, _ := ms.At(i).Obj().(*types.Func)
 			if m != nil {
-				methods[m.Name()] = NewFunc(m)
+				methods[m.Name()] = u.Func(m)
 			}
 		}
 		if _, isPtr := t.(*types.Pointer); !isPtr {
-			ptrMethods := Methods(types.NewPointer(t))
+			ptrMethods := u.Methods(types.NewPointer(t))
 			for n, m := range ptrMethods {
 				if _, ok := methods[n]; !ok {
 					methods[n] = m
@@ -640,73 +941,101 @@ func under(v any) types.Type {
 			return nil
 		}
 		return t.Signature.TypesSignature.Underlying()
+	case *Alias:
+		if t == nil {
+			return nil
+		}
+		return t.TypesAlias.Underlying()
 	default:
 		return nil
 	}
 }
 
 func implements(t any, iface any) bool {
-	if t == nil || iface == nil {
+	_t, _iface := unalias(t), interfaceOfValue(iface)
+	if _t == nil || _iface == nil {
 		return false
 	}
 
-	var (
-		_t     types.Type
-		_iface *types.Interface
-	)
+	return types.Implements(_t, _iface) || types.Implements(types.NewPointer(_t), _iface)
+}
 
+// typeOfValue returns a type of a type or an object.
+func typeOfValue(t any) types.Type {
 	switch t := t.(type) {
 	case types.Type:
-		if t == nil {
-			return false
-		}
-		_t = t
+		return t
 	case *Type:
 		if t == nil {
-			return false
+			return nil
 		}
-		_t = t.TypesType
+		return t.TypesType
 	case *TypeName:
 		if t == nil || t.Type == nil {
-			return false
+			return nil
+		}
+		return t.Type.TypesType
+	case *TypeParam:
+		if t == nil {
+			return nil
+		}
+		return t.TypesTypeParam
+	case *Alias:
+		if t == nil {
+			return nil
 		}
-		_t = t.Type.TypesType
+		return t.TypesAlias
 	case Object:
 		if t == nil {
-			return false
+			return nil
 		}
-		_t = t.TypesObject().Type()
+		return t.TypesObject().Type()
 	case types.Object:
 		if t == nil {
-			return false
+			return nil
 		}
-		_t = t.Type()
-	default:
-		return false
+		return t.Type()
 	}
+	return nil
+}
 
+// interfaceOfValue returns an interface type of a type or an object.
+func interfaceOfValue(iface any) *types.Interface {
 	switch iface := iface.(type) {
 	case *types.Interface:
-		_iface = iface
+		return iface
 	case types.Type:
-		_iface, _ = under(iface).(*types.Interface)
+		i, _ := under(iface).(*types.Interface)
+		return i
 	case *Type:
-		_iface, _ = under(iface.TypesType.Underlying()).(*types.Interface)
+		if iface == nil {
+			return nil
+		}
+		i, _ := under(iface.TypesType.Underlying()).(*types.Interface)
+		return i
+	case *Interface:
+		if iface == nil {
+			return nil
+		}
+		return iface.TypesInterface
+	case *TypeParam:
+		if iface == nil {
+			return nil
+		}
+		// the underlying type of a type parameter is its constraint
+		i, _ := iface.TypesTypeParam.Underlying().(*types.Interface)
+		return i
 	case Object:
-		_iface, _ = under(iface.TypesObject().Type().Underlying()).(*types.Interface)
+		if iface == nil {
+			return nil
+		}
+		i, _ := under(iface.TypesObject().Type().Underlying()).(*types.Interface)
+		return i
 	case types.Object:
-		_iface, _ = under(iface.Type()).(*types.Interface)
-	case *Interface:
-		_iface = iface.TypesInterface
-	default:
-		return false
+		i, _ := under(iface.Type()).(*types.Interface)
+		return i
 	}
-
-	if _t == nil || _iface == nil {
-		return false
-	}
-
-	return types.Implements(_t, _iface) || types.Implements(types.NewPointer(_t), _iface)
+	return nil
 }
 
 func identical(t1, t2 any) bool {
@@ -762,5 +1091,5 @@ func identical(t1, t2 any) bool {
 		_t2 = t2.Type()
 	}
 
-	return _t1 != nil && _t2 != nil && types.Identical(_t1, _t2)
+	return _t1 != nil && _t2 != nil && types.Identical(types.Unalias(_t1), types.Unalias(_t2))
 }
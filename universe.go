@@ -0,0 +1,48 @@
+package knife
+
+import (
+	"go/types"
+	"reflect"
+	"sync"
+)
+
+// Universe is a set of knife objects which are created from go/types objects.
+// Within a universe, the same go/types object is always converted to
+// the same knife object.
+// A [Knife] and a cutter.Cutter own a universe and it is released with them.
+// A Universe is safe for concurrent use.
+type Universe struct {
+	mu      sync.Mutex
+	objects map[cacheKey]any
+	// ctxt deduplicates instances which are created by Instantiate
+	ctxt *types.Context
+}
+
+// NewUniverse creates an empty [Universe].
+func NewUniverse() *Universe {
+	return &Universe{
+		objects: make(map[cacheKey]any),
+		ctxt:    types.NewContext(),
+	}
+}
+
+type cacheKey struct {
+	typ reflect.Type
+	key any
+}
+
+// load returns a cached object of the key or creates a new object.
+// The new object is cached before init is called,
+// so that init can refer the object recursively.
+// The caller must hold u.mu.
+func load[T any](u *Universe, key any, init func(*T)) *T {
+	k := cacheKey{typ: reflect.TypeFor[T](), key: key}
+	if v, ok := u.objects[k]; ok {
+		return v.(*T)
+	}
+
+	var obj T
+	u.objects[k] = &obj
+	init(&obj)
+	return &obj
+}
@@ -0,0 +1,60 @@
+package knife
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUniverse(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "fmt")
+	pkg := k.Packages()[0].Types
+
+	u := NewUniverse()
+	if u.Package(pkg) != u.Package(pkg) {
+		t.Error("a universe must return the same package")
+	}
+
+	if NewPackage(pkg) == u.Package(pkg) {
+		t.Error("different universes must not share packages")
+	}
+
+	f := u.Package(pkg).Funcs()["Println"]
+	if f.Signature != u.Signature(f.Signature.TypesSignature) {
+		t.Error("a universe must return the same signature")
+	}
+
+	if f.Package != u.Package(pkg) {
+		t.Error("an object must refer the package in the same universe")
+	}
+
+	typ := u.Package(pkg).Types()["Stringer"].Type
+	if typ.Interface() != u.Interface(typ.Interface().TypesInterface) {
+		t.Error("a type must create objects in its universe")
+	}
+}
+
+func TestUniverse_Concurrent(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{}, "fmt", "strings")
+
+	u := NewUniverse()
+	var wg sync.WaitGroup
+	got := make([]*Package, 8)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pkg := k.Packages()[i%2].Types
+			got[i] = u.Package(pkg)
+			for _, f := range got[i].Funcs() {
+				_ = f.Signature.Params
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range got {
+		if got[i] != got[i%2] {
+			t.Errorf("[%d] a universe must return the same package", i)
+		}
+	}
+}
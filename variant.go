@@ -0,0 +1,90 @@
+package knife
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// VariantPolicy is a policy for test variants of packages.
+// When tests are loaded, [packages.Load] returns a package p,
+// its test-augmented variant "p [p.test]", an external test package
+// "p_test [p.test]" and a synthesized test main package "p.test".
+type VariantPolicy int
+
+const (
+	// VariantsCollapse merges a test-augmented package with its base package,
+	// keeps an external test package separate and drops a synthesized test main package.
+	VariantsCollapse VariantPolicy = iota
+	// VariantsAll keeps all packages which are returned by [packages.Load].
+	VariantsAll
+)
+
+var _ flag.Value = (*VariantPolicy)(nil)
+
+func (p *VariantPolicy) String() string {
+	if p == nil {
+		return ""
+	}
+
+	switch *p {
+	case VariantsCollapse:
+		return "collapse"
+	case VariantsAll:
+		return "all"
+	}
+	return fmt.Sprintf("VariantPolicy(%d)", int(*p))
+}
+
+// Set implements [flag.Value].
+func (p *VariantPolicy) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "collapse":
+		*p = VariantsCollapse
+	case "all":
+		*p = VariantsAll
+	default:
+		return fmt.Errorf("unknown variant policy %q: expected collapse or all", s)
+	}
+	return nil
+}
+
+// FilterVariants filters the packages by the policy.
+func FilterVariants(pkgs []*packages.Package, policy VariantPolicy) []*packages.Package {
+	if policy == VariantsAll {
+		return pkgs
+	}
+
+	augmented := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if isTestAugmented(pkg) {
+			augmented[pkg.PkgPath] = true
+		}
+	}
+
+	filtered := make([]*packages.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		switch {
+		case isTestMain(pkg):
+			continue
+		case pkg.ForTest == "" && augmented[pkg.PkgPath]:
+			// the test-augmented variant includes all files of the base package
+			continue
+		}
+		filtered = append(filtered, pkg)
+	}
+
+	return filtered
+}
+
+// isTestAugmented reports whether the package is "p [p.test]".
+func isTestAugmented(pkg *packages.Package) bool {
+	return pkg.ForTest != "" && pkg.PkgPath == pkg.ForTest
+}
+
+// isTestMain reports whether the package is a synthesized test main package "p.test".
+func isTestMain(pkg *packages.Package) bool {
+	return pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test") && pkg.PkgPath == pkg.ID
+}
@@ -0,0 +1,80 @@
+package knife
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterVariants(t *testing.T) {
+	type pkg struct {
+		Path          string
+		ForTest       string
+		IsTestVariant bool
+		Funcs         []string
+	}
+
+	cases := []struct {
+		name     string
+		variants VariantPolicy
+		want     []pkg
+	}{
+		{
+			name:     "collapse",
+			variants: VariantsCollapse,
+			want: []pkg{
+				{Path: "github.com/gostaticanalysis/knife/testdata/variants", ForTest: "github.com/gostaticanalysis/knife/testdata/variants", IsTestVariant: true, Funcs: []string{"F", "helper"}},
+				{Path: "github.com/gostaticanalysis/knife/testdata/variants_test", ForTest: "github.com/gostaticanalysis/knife/testdata/variants", IsTestVariant: true, Funcs: []string{"Example"}},
+			},
+		},
+		{
+			name:     "all",
+			variants: VariantsAll,
+			want: []pkg{
+				{Path: "github.com/gostaticanalysis/knife/testdata/variants", Funcs: []string{"F"}},
+				{Path: "github.com/gostaticanalysis/knife/testdata/variants", ForTest: "github.com/gostaticanalysis/knife/testdata/variants", IsTestVariant: true, Funcs: []string{"F", "helper"}},
+				{Path: "github.com/gostaticanalysis/knife/testdata/variants.test", Funcs: []string{"main"}},
+				{Path: "github.com/gostaticanalysis/knife/testdata/variants_test", ForTest: "github.com/gostaticanalysis/knife/testdata/variants", IsTestVariant: true, Funcs: []string{"Example"}},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := &KnifeOption{Tests: true, Variants: tt.variants}
+			k := newTestKnife(t, opt, "./testdata/variants")
+
+			var got []pkg
+			for _, p := range k.KnifePackages() {
+				got = append(got, pkg{
+					Path:          p.Path,
+					ForTest:       p.ForTest,
+					IsTestVariant: p.IsTestVariant,
+					Funcs:         p.FuncNames(),
+				})
+			}
+
+			slices.SortStableFunc(got, func(a, b pkg) int {
+				switch {
+				case a.Path < b.Path:
+					return -1
+				case a.Path > b.Path:
+					return 1
+				}
+				return len(a.Funcs) - len(b.Funcs)
+			})
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i].Path != tt.want[i].Path ||
+					got[i].ForTest != tt.want[i].ForTest ||
+					got[i].IsTestVariant != tt.want[i].IsTestVariant ||
+					!slices.Equal(got[i].Funcs, tt.want[i].Funcs) {
+					t.Errorf("[%d] got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package knife
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ImportPath is a chain of imports from a package to another package.
+type ImportPath struct {
+	// Root is the package where the path starts.
+	Root *Package
+	// Steps is empty if Root is the package which the path reaches.
+	Steps []*ImportStep
+}
+
+var _ fmt.Stringer = (*ImportPath)(nil)
+
+// Packages returns the packages on the path in the import order.
+func (p *ImportPath) Packages() []*Package {
+	if p.Root == nil {
+		return nil
+	}
+
+	pkgs := []*Package{p.Root}
+	for _, s := range p.Steps {
+		pkgs = append(pkgs, s.To)
+	}
+	return pkgs
+}
+
+func (p *ImportPath) String() string {
+	paths := make([]string, 0, len(p.Steps)+1)
+	for _, pkg := range p.Packages() {
+		paths = append(paths, pkg.Path)
+	}
+	return strings.Join(paths, " -> ")
+}
+
+// ImportStep is an import of a package by another package.
+type ImportStep struct {
+	From *Package
+	To   *Package
+	pos  token.Pos
+}
+
+var _ fmt.Stringer = (*ImportStep)(nil)
+
+// Pos returns a position of the import spec.
+// It is [token.NoPos] if the importing package does not have syntax.
+func (s *ImportStep) Pos() token.Pos {
+	return s.pos
+}
+
+func (s *ImportStep) String() string {
+	return s.From.Path + " -> " + s.To.Path
+}
+
+// Why returns all shortest import paths from pkgs to the package which has the path.
+// Import specs of each step are found from syntax of the packages and their dependencies.
+// If a package in pkgs has the path, it returns a path which has no steps from the package.
+func (u *Universe) Why(pkgs []*packages.Package, path string) []*ImportPath {
+	syntax := make(map[*types.Package]*packages.Package)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Types != nil {
+			syntax[pkg.Types] = pkg
+		}
+	})
+
+	// breadth-first search from all packages to record shortest predecessors
+	dists := make(map[*Package]int)
+	preds := make(map[*Package][]*Package)
+	var queue []*Package
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+
+		kpkg := u.Package(pkg.Types)
+		if kpkg.Path == path {
+			return []*ImportPath{{Root: kpkg}}
+		}
+
+		if _, ok := dists[kpkg]; !ok {
+			dists[kpkg] = 0
+			queue = append(queue, kpkg)
+		}
+	}
+
+	var targets []*Package
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		// all shortest paths have been found
+		if len(targets) > 0 && dists[pkg] >= dists[targets[0]] {
+			break
+		}
+
+		for _, imp := range pkg.Imports() {
+			d, ok := dists[imp]
+			switch {
+			case !ok:
+				dists[imp] = dists[pkg] + 1
+				preds[imp] = []*Package{pkg}
+				queue = append(queue, imp)
+				if imp.Path == path {
+					targets = append(targets, imp)
+				}
+			case d == dists[pkg]+1:
+				preds[imp] = append(preds[imp], pkg)
+			}
+		}
+	}
+
+	var paths []*ImportPath
+	var walk func(pkg *Package, steps []*ImportStep)
+	walk = func(pkg *Package, steps []*ImportStep) {
+		if dists[pkg] == 0 {
+			paths = append(paths, &ImportPath{Root: pkg, Steps: slices.Clone(steps)})
+			return
+		}
+
+		for _, pred := range preds[pkg] {
+			step := &ImportStep{
+				From: pred,
+				To:   pkg,
+				pos:  importSpecPos(syntax[pred.TypesPackage], pkg.TypesPackage),
+			}
+			walk(pred, append([]*ImportStep{step}, steps...))
+		}
+	}
+
+	for _, target := range targets {
+		walk(target, nil)
+	}
+
+	slices.SortStableFunc(paths, func(a, b *ImportPath) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	// test variants of a package may have same import paths
+	return slices.CompactFunc(paths, func(a, b *ImportPath) bool {
+		return a.String() == b.String()
+	})
+}
+
+// importSpecPos returns a position of the import spec in pkg which imports imported.
+func importSpecPos(pkg *packages.Package, imported *types.Package) token.Pos {
+	if pkg == nil {
+		return token.NoPos
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			if importedBy(pkg.TypesInfo, spec) == imported.Path() {
+				return spec.Pos()
+			}
+		}
+	}
+
+	return token.NoPos
+}
+
+func importedBy(info *types.Info, spec *ast.ImportSpec) string {
+	if info != nil {
+		if name := info.PkgNameOf(spec); name != nil {
+			return name.Imported().Path()
+		}
+	}
+
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return ""
+	}
+	return path
+}
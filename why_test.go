@@ -0,0 +1,93 @@
+package knife
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestKnife_Why(t *testing.T) {
+	const prefix = "github.com/gostaticanalysis/knife/testdata/why/"
+
+	cases := []struct {
+		name    string
+		pattern string
+		to      string
+		want    []string
+	}{
+		{
+			name:    "all shortest paths",
+			pattern: "./testdata/why/app",
+			to:      prefix + "heavy",
+			want: []string{
+				"app -> l1 -> heavy: app.go:4:2 l1.go:3:8",
+				"app -> l2 -> heavy: app.go:5:2 l2.go:4:2",
+			},
+		},
+		{
+			name:    "direct",
+			pattern: "./testdata/why/l2",
+			to:      prefix + "heavy",
+			want:    []string{"l2 -> heavy: l2.go:4:2"},
+		},
+		{
+			name:    "multiple roots",
+			pattern: "./testdata/why/...",
+			to:      prefix + "l1",
+			want:    []string{"l1: "},
+		},
+		{
+			name:    "root",
+			pattern: "./testdata/why/l1",
+			to:      prefix + "l1",
+			want:    []string{"l1: "},
+		},
+		{
+			name:    "not imported",
+			pattern: "./testdata/why/heavy",
+			to:      prefix + "l1",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newTestKnife(t, &KnifeOption{Tests: true}, tt.pattern)
+
+			var got []string
+			for _, path := range k.Why(tt.to) {
+				var poss []string
+				for _, step := range path.Steps {
+					pos := k.Position(step)
+					poss = append(poss, fmt.Sprintf("%s:%d:%d", filepath.Base(pos.Filename), pos.Line, pos.Column))
+				}
+				got = append(got, strings.ReplaceAll(path.String(), prefix, "")+": "+strings.Join(poss, " "))
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Why(t *testing.T) {
+	k := newTestKnife(t, &KnifeOption{Tests: true}, "./testdata/why/...")
+
+	var buf strings.Builder
+	tmpl := `{{pkg.Name}}={{range why "github.com/gostaticanalysis/knife/testdata/why/heavy"}}{{len .Steps}}{{end}}`
+	for _, pkg := range k.Packages() {
+		if err := k.Execute(&buf, pkg, tmpl, &ExecuteOption{}); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		buf.WriteString(" ")
+	}
+
+	got := strings.Fields(buf.String())
+	slices.Sort(got)
+	if want := []string{"app=22", "heavy=0", "l1=1", "l2=1"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}